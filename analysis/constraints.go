@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var ErrConstraintsNotMet = errors.New("analysis: constraints not met within attempt budget")
+
+// Constraints are the targets a generated maze should meet, a zero
+// value for a field means it is not checked.
+type Constraints struct {
+	MinSolution  int     // minimal solution length in pixels
+	MinDeadEnds  float64 // minimal dead end ratio
+	MaxDeadEnds  float64 // maximal dead end ratio
+	MaxBranching int     // maximal junctions passed on the solution
+}
+
+// IsZero returns true when no target is set
+func (c Constraints) IsZero() bool {
+	return c == Constraints{}
+}
+
+// Failures returns a description for every target the metrics miss
+func (c Constraints) Failures(m *Metrics) []string {
+	failures := make([]string, 0)
+	if c.MinSolution > 0 && m.SolutionLength < c.MinSolution {
+		failures = append(failures, fmt.Sprintf("solution length %d < %d", m.SolutionLength, c.MinSolution))
+	}
+	if c.MinDeadEnds > 0 && m.DeadEndRatio < c.MinDeadEnds {
+		failures = append(failures, fmt.Sprintf("dead end ratio %.3f < %.3f", m.DeadEndRatio, c.MinDeadEnds))
+	}
+	if c.MaxDeadEnds > 0 && m.DeadEndRatio > c.MaxDeadEnds {
+		failures = append(failures, fmt.Sprintf("dead end ratio %.3f > %.3f", m.DeadEndRatio, c.MaxDeadEnds))
+	}
+	if c.MaxBranching > 0 && m.Branching > c.MaxBranching {
+		failures = append(failures, fmt.Sprintf("branching %d > %d", m.Branching, c.MaxBranching))
+	}
+	return failures
+}
+
+// Report describes the outcome of a constraint driven generation
+type Report struct {
+	Attempts  int      // number of mazes generated
+	Metrics   *Metrics // metrics of the returned maze
+	Failures  []string // targets the returned maze misses
+	Satisfied bool
+}
+
+func (r Report) String() string {
+	if r.Satisfied {
+		return fmt.Sprintf("constraints met after %d attempt(s): %s", r.Attempts, r.Metrics)
+	}
+	return fmt.Sprintf("constraints not met after %d attempt(s): %s [%s]", r.Attempts, r.Metrics, strings.Join(r.Failures, ", "))
+}
+
+// Generate will keep fetching mazes from the source until one meets the
+// constraints or the attempts are used up. When no maze qualifies the one
+// missing the least targets is returned together with ErrConstraintsNotMet.
+func Generate(source func() (*builder.MazeImageMatrix, error), c Constraints, attempts int) (*builder.MazeImageMatrix, *Report, error) {
+	var best *builder.MazeImageMatrix
+	report := &Report{}
+
+	for report.Attempts < attempts || report.Attempts == 0 {
+		matrix, err := source()
+		if err != nil {
+			return nil, nil, err
+		}
+		report.Attempts++
+		metrics, err := Measure(matrix)
+		if err != nil {
+			return nil, nil, err
+		}
+		failures := c.Failures(metrics)
+		if best == nil || len(failures) < len(report.Failures) || (len(failures) == len(report.Failures) && metrics.SolutionLength > report.Metrics.SolutionLength) {
+			best, report.Metrics, report.Failures = matrix, metrics, failures
+		}
+		if len(failures) == 0 {
+			report.Satisfied = true
+			return best, report, nil
+		}
+	}
+
+	return best, report, ErrConstraintsNotMet
+}
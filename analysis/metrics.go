@@ -0,0 +1,215 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/solver"
+)
+
+// Metrics holds the measured values of a maze
+type Metrics struct {
+	Area           int         `json:"area"`            // walkable pixels
+	SolutionLength int         `json:"solution_length"` // pixels on the shortest solution path
+	SolutionRatio  float64     `json:"solution_ratio"`  // share of the area used by the solution
+	DeadEnds       int         `json:"dead_ends"`       // path pixels with only one way out
+	Junctions      int         `json:"junctions"`       // path pixels with three or more ways out
+	DeadEndRatio   float64     `json:"dead_end_ratio"`  // dead ends per maze cell
+	Corridors      map[int]int `json:"corridors"`       // corridor length histogram, length => count
+	River          float64     `json:"river"`           // average length of corridors ending in a dead end
+	Turns          int         `json:"turns"`           // direction changes while following the shortest solution
+	Cycles         int         `json:"cycles"`          // independent loops in the maze
+	Branching      int         `json:"branching"`       // junctions passed while following the shortest solution
+	WrongTurns     int         `json:"wrong_turns"`     // branches the walker entered and had to abandon
+}
+
+// Measure will solve the given maze and collect its metrics, it works on a
+// copy so start and end placed for the measurement are not left on m
+func Measure(m *builder.MazeImageMatrix) (*Metrics, error) {
+	m = m.Copy()
+	metrics := &Metrics{Corridors: make(map[int]int)}
+	bounds := m.Bounds()
+	edges := 0
+	// solving first so start and end are marked
+	walker, err := solver.NewWalker(m)
+	if err != nil {
+		return nil, err
+	}
+	result, err := walker.Solve(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if !m.Has(x, y, builder.PATH) {
+				continue
+			}
+			metrics.Area++
+			// only count right and down so every edge is seen once
+			if x < bounds.Max.X && m.Has(x+1, y, builder.PATH) {
+				edges++
+			}
+			if y < bounds.Max.Y && m.Has(x, y+1, builder.PATH) {
+				edges++
+			}
+			p := solver.NewPosition(x, y)
+			switch n := ways(m, p); true {
+			case n == 1 && !isGoal(m, p):
+				metrics.DeadEnds++
+			case n >= 3:
+				metrics.Junctions++
+			}
+		}
+	}
+
+	if cells := m.I.GetHeight() * m.I.GetWidth(); cells > 0 {
+		metrics.DeadEndRatio = float64(metrics.DeadEnds) / float64(cells)
+	}
+
+	// cyclomatic number of the path graph: E - V + components
+	metrics.Cycles = edges - metrics.Area + components(m)
+	corridors(m, metrics)
+
+	traces := result.GetTraces()
+	for i := 1; i < len(traces); i++ {
+		prev, t := traces[i-1], traces[i]
+		if solver.OK != (solver.OK&t.T) && (prev.X != t.X || prev.Y != t.Y) && ways(m, solver.NewPosition(prev.X, prev.Y)) >= 3 {
+			metrics.WrongTurns++
+		}
+	}
+
+	// the walker route depends on the order it tries directions, in a maze
+	// with loops it is not the solution so the shortest route is measured
+	path, err := solver.Route(m, walker.GetStart(), walker.GetEnd())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range path {
+		if ways(m, p) >= 3 {
+			metrics.Branching++
+		}
+	}
+
+	metrics.SolutionLength = len(path)
+	if metrics.Area > 0 {
+		metrics.SolutionRatio = float64(metrics.SolutionLength) / float64(metrics.Area)
+	}
+
+	moves := solver.Moves(path)
+	for i := 1; i < len(moves); i++ {
+		if moves[i] != moves[i-1] {
+			metrics.Turns++
+		}
+	}
+
+	return metrics, nil
+}
+
+// Difficulty returns a combined score, every wrong turn and decision on the
+// solution weighs more than a single step.
+func (m Metrics) Difficulty() float64 {
+	return float64(m.SolutionLength) + 4*float64(m.WrongTurns) + 2*float64(m.Branching)
+}
+
+// String returns the metrics as readable text
+func (m Metrics) String() string {
+	return fmt.Sprintf(
+		"solution length: %d, dead ends: %d (ratio %.3f), junctions: %d, cycles: %d, branching: %d, wrong turns: %d",
+		m.SolutionLength,
+		m.DeadEnds,
+		m.DeadEndRatio,
+		m.Junctions,
+		m.Cycles,
+		m.Branching,
+		m.WrongTurns,
+	)
+}
+
+// components returns the number of separate path areas
+func components(m *builder.MazeImageMatrix) int {
+	var count int
+	bounds := m.Bounds()
+	seen := make(map[solver.Position]bool)
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			p := solver.NewPosition(x, y)
+			if !m.Has(x, y, builder.PATH) || seen[p] {
+				continue
+			}
+			count++
+			seen[p] = true
+			stack := []solver.Position{p}
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, n := range solver.Neighbours(m, p) {
+					if !seen[n] {
+						seen[n] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
+// corridors walks from every node (dead end, junction, start or end) along
+// its corridors to the next node and fills the histogram and river factor.
+func corridors(m *builder.MazeImageMatrix, metrics *Metrics) {
+	var branches, branchLength int
+	bounds := m.Bounds()
+	isNode := func(p solver.Position) bool {
+		return ways(m, p) != 2 || isGoal(m, p)
+	}
+	isDeadEnd := func(p solver.Position) bool {
+		return ways(m, p) == 1 && !isGoal(m, p)
+	}
+	seen := make(map[[2]solver.Position]bool)
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			node := solver.NewPosition(x, y)
+			if !m.Has(x, y, builder.PATH) || !isNode(node) {
+				continue
+			}
+			for _, first := range solver.Neighbours(m, node) {
+				// every corridor is found from both ends, it is keyed by node and first step
+				if seen[[2]solver.Position{node, first}] {
+					continue
+				}
+				prev, next, length := node, first, 1
+				for !isNode(next) {
+					for _, n := range solver.Neighbours(m, next) {
+						if n != prev {
+							prev, next = next, n
+							break
+						}
+					}
+					length++
+				}
+				seen[[2]solver.Position{node, first}] = true
+				seen[[2]solver.Position{next, prev}] = true
+				metrics.Corridors[length]++
+				if isDeadEnd(node) || isDeadEnd(next) {
+					branches++
+					branchLength += length
+				}
+			}
+		}
+	}
+	if branches > 0 {
+		metrics.River = float64(branchLength) / float64(branches)
+	}
+}
+
+// ways returns the number of path pixels next to the given position
+func ways(m *builder.MazeImageMatrix, p solver.Position) int {
+	return len(solver.Neighbours(m, p))
+}
+
+// isGoal checks if given position is marked as start or end
+func isGoal(m *builder.MazeImageMatrix, p solver.Position) bool {
+	return m.Has(p.X(), p.Y(), builder.START) || m.Has(p.X(), p.Y(), builder.END)
+}
@@ -18,6 +18,8 @@ type MazeImageBuilder struct {
 	ratio      uint
 	wall_color *color.RGBA
 	path_color *color.RGBA
+	// number of random cost regions painted on generated mazes
+	cost_regions int
 }
 
 func NewMazeImageBuilder(height, width int) *MazeImageBuilder {
@@ -62,6 +64,26 @@ func (m *MazeImageBuilder) GetRatio() uint {
 	return m.ratio
 }
 
+// SetCostRegions sets the number of random cost regions Generate paints
+// on the maze, see MazeImageMatrix.PaintCostRegions
+func (m *MazeImageBuilder) SetCostRegions(n int) {
+	m.cost_regions = n
+}
+
+func (m *MazeImageBuilder) GetCostRegions() int {
+	return m.cost_regions
+}
+
+// GetHeight returns the height of the maze in cells
+func (m *MazeImageBuilder) GetHeight() int {
+	return m.height
+}
+
+// GetWidth returns the width of the maze in cells
+func (m *MazeImageBuilder) GetWidth() int {
+	return m.width
+}
+
 // String will return url for fetching the maze image
 func (m MazeImageBuilder) String() string {
 	return fmt.Sprintf(
@@ -82,10 +104,10 @@ func (m MazeImageBuilder) String() string {
 // GetMatrix will return and create matrix based on fetched image
 func (m *MazeImageBuilder) GetMatrix() (*MazeImageMatrix, error) {
 	resp, err := http.Get(m.String())
-	defer resp.Body.Close()
 	if err != nil {
 		return nil, err
 	} else {
+		defer resp.Body.Close()
 		if image, err := NewMazeImageMatrix(resp.Body, m); err != nil {
 			return nil, err
 		} else {
@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+)
+
+// MaxCost is the highest traversal cost a pixel can have
+const MaxCost = 9
+
+// mud is the color path pixels are shaded to when their cost increases
+var mud = color.RGBA{120, 80, 30, 255}
+
+// Cost returns the cost to step on the given position, 1 when the matrix
+// has no cost layer or the cost is not set.
+func (i MazeImageMatrix) Cost(x, y int) int {
+	if i.C == nil || i.C[y][x] == 0 {
+		return 1
+	}
+	return int(i.C[y][x])
+}
+
+// SetCost sets the traversal cost of a single position
+func (i *MazeImageMatrix) SetCost(x, y int, cost uint8) {
+	if i.C == nil {
+		i.C = make([][]uint8, len(i.M))
+		for y := range i.M {
+			i.C[y] = make([]uint8, len(i.M[y]))
+		}
+	}
+	if cost > MaxCost {
+		cost = MaxCost
+	}
+	i.C[y][x] = cost
+}
+
+// PaintCost sets the cost for all path positions in the given rectangle
+func (i *MazeImageMatrix) PaintCost(r image.Rectangle, cost uint8) {
+	r = r.Intersect(image.Rect(0, 0, len(i.M[0]), len(i.M)))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if i.Has(x, y, PATH) {
+				i.SetCost(x, y, cost)
+			}
+		}
+	}
+}
+
+// PaintCostRegions paints the given number of random rectangles, each up to
+// a quarter of the maze in size, with a random cost between 2 and MaxCost.
+func (i *MazeImageMatrix) PaintCostRegions(r *rand.Rand, regions int) {
+	width, height := len(i.M[0]), len(i.M)
+	for n := 0; n < regions; n++ {
+		w, h := 1+r.Intn(width/4+1), 1+r.Intn(height/4+1)
+		x, y := r.Intn(width), r.Intn(height)
+		i.PaintCost(image.Rect(x, y, x+w, y+h), uint8(2+r.Intn(MaxCost-1)))
+	}
+}
+
+// copyCost returns a deep copy of the cost layer
+func copyCost(c [][]uint8) [][]uint8 {
+	if c == nil {
+		return nil
+	}
+	copied := make([][]uint8, len(c))
+	for y := range c {
+		copied[y] = make([]uint8, len(c[y]))
+		copy(copied[y], c[y])
+	}
+	return copied
+}
+
+// costColor returns the path color shaded by the cost of the position
+func (i MazeImageMatrix) costColor(x, y int) color.Color {
+	cost := i.Cost(x, y)
+	if cost <= 1 {
+		return i.I.path_color
+	}
+	f := float64(cost-1) / float64(MaxCost-1) * 0.8
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-f) + float64(b)*f)
+	}
+	return color.RGBA{mix(i.I.path_color.R, mud.R), mix(i.I.path_color.G, mud.G), mix(i.I.path_color.B, mud.B), 255}
+}
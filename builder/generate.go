@@ -0,0 +1,169 @@
+package builder
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Generator carves the passages of a grid that starts with all walls
+type Generator func(g *Grid, r *rand.Rand)
+
+var Generators = map[string]Generator{
+	"backtracker": backtracker,
+	"prim":        prim,
+	"braid":       braid,
+}
+
+// GetGenerator returns the generator registered by the given name
+func GetGenerator(name string) (Generator, error) {
+	if g, ok := Generators[name]; ok {
+		return g, nil
+	}
+	return nil, fmt.Errorf("builder: unknown generator %q, available: %v", name, GeneratorNames())
+}
+
+// GeneratorNames returns the sorted names of all generators
+func GeneratorNames() []string {
+	names := make([]string, 0, len(Generators))
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate builds a maze locally with the given generator instead of
+// fetching one, the matrix has the same layout as a fetched maze with an
+// opening in the top and bottom wall. The same seed gives the same maze,
+// with the same cost regions when the builder has any set.
+func (m *MazeImageBuilder) Generate(name string, seed int64) (*MazeImageMatrix, error) {
+	generator, err := GetGenerator(name)
+	if err != nil {
+		return nil, err
+	}
+	if m.height < 1 || m.width < 1 {
+		return nil, fmt.Errorf("builder: invalid dimensions %dx%d", m.height, m.width)
+	}
+
+	// border on the first row and column, walls with a pixel for every
+	// cell and a path margin on the last row and column
+	height, width := 2*m.height+3, 2*m.width+3
+	matrix := make([][]MatrixToken, height)
+	for y := range matrix {
+		matrix[y] = make([]MatrixToken, width)
+		for x := range matrix[y] {
+			switch {
+			case y == 0 || x == 0:
+				matrix[y][x] = BORDER
+			case y == height-1 || x == width-1:
+				matrix[y][x] = PATH
+			case y%2 == 0 && x%2 == 0 && y < height-2 && x < width-2:
+				matrix[y][x] = PATH
+			default:
+				matrix[y][x] = WALL
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	grid := NewGrid(&MazeImageMatrix{M: matrix, I: m})
+	generator(grid, r)
+	grid.Cell(r.Intn(grid.Width), 0).Link(NORTH)
+	grid.Cell(r.Intn(grid.Width), grid.Height-1).Link(SOUTH)
+	generated := grid.Matrix()
+	if m.cost_regions > 0 {
+		generated.PaintCostRegions(r, m.cost_regions)
+	}
+	return generated, nil
+}
+
+// backtracker carves a perfect maze with a random depth first walk, it
+// gives long corridors with few junctions
+func backtracker(g *Grid, r *rand.Rand) {
+	visited := make(map[*Cell]bool)
+	stack := []*Cell{g.Cell(r.Intn(g.Width), r.Intn(g.Height))}
+	visited[stack[0]] = true
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		sides := make([]Side, 0, 4)
+		for _, s := range Sides {
+			if n := current.Neighbour(s); n != nil && !visited[n] {
+				sides = append(sides, s)
+			}
+		}
+		if len(sides) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		s := sides[r.Intn(len(sides))]
+		current.Link(s)
+		visited[current.Neighbour(s)] = true
+		stack = append(stack, current.Neighbour(s))
+	}
+}
+
+// prim carves a perfect maze by growing from a random cell and linking a
+// random frontier cell every step, it gives many short dead ends
+func prim(g *Grid, r *rand.Rand) {
+	in := make(map[*Cell]bool)
+	frontier := make([]*Cell, 0)
+	queued := make(map[*Cell]bool)
+	add := func(c *Cell) {
+		in[c] = true
+		for _, n := range c.neighbours() {
+			if !in[n] && !queued[n] {
+				queued[n] = true
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	add(g.Cell(r.Intn(g.Width), r.Intn(g.Height)))
+	for len(frontier) > 0 {
+		i := r.Intn(len(frontier))
+		current := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		sides := make([]Side, 0, 4)
+		for _, s := range Sides {
+			if n := current.Neighbour(s); n != nil && in[n] {
+				sides = append(sides, s)
+			}
+		}
+		current.Link(sides[r.Intn(len(sides))])
+		add(current)
+	}
+}
+
+// braid carves a perfect maze and removes most dead ends by linking them
+// to a neighbour, it gives a maze with loops
+func braid(g *Grid, r *rand.Rand) {
+	backtracker(g, r)
+	for _, row := range g.Cells {
+		for _, c := range row {
+			if len(c.Neighbours()) != 1 || r.Intn(4) == 0 {
+				continue
+			}
+			sides := make([]Side, 0, 3)
+			for _, s := range Sides {
+				if n := c.Neighbour(s); n != nil && !c.Linked(s) {
+					sides = append(sides, s)
+				}
+			}
+			if len(sides) > 0 {
+				c.Link(sides[r.Intn(len(sides))])
+			}
+		}
+	}
+}
+
+// neighbours returns the adjacent cells, walls are ignored
+func (c *Cell) neighbours() []*Cell {
+	list := make([]*Cell, 0, 4)
+	for _, s := range Sides {
+		if n := c.Neighbour(s); n != nil {
+			list = append(list, n)
+		}
+	}
+	return list
+}
@@ -0,0 +1,229 @@
+package builder
+
+import "image"
+
+type Side uint8
+
+const (
+	NORTH Side = 1 << iota
+	EAST
+	SOUTH
+	WEST
+)
+
+// Opposite returns the side facing this one
+func (s Side) Opposite() Side {
+	switch s {
+	case NORTH:
+		return SOUTH
+	case EAST:
+		return WEST
+	case SOUTH:
+		return NORTH
+	case WEST:
+		return EAST
+	default:
+		return 0
+	}
+}
+
+// delta returns the step to the neighbouring cell
+func (s Side) delta() image.Point {
+	switch s {
+	case NORTH:
+		return image.Pt(0, -1)
+	case EAST:
+		return image.Pt(1, 0)
+	case SOUTH:
+		return image.Pt(0, 1)
+	case WEST:
+		return image.Pt(-1, 0)
+	default:
+		return image.ZP
+	}
+}
+
+var Sides = [4]Side{NORTH, EAST, SOUTH, WEST}
+
+// Cell is a single maze cell, a linked side has no wall. A linked side on
+// the edge of the grid is an opening in the outer wall.
+type Cell struct {
+	X, Y  int
+	Token MatrixToken // token of the cell pixel
+	links Side
+	grid  *Grid
+}
+
+// Linked checks if there is no wall on the given side
+func (c *Cell) Linked(s Side) bool {
+	return s == (s & c.links)
+}
+
+// Neighbour returns the adjacent cell on the given side, walls are ignored
+// and nil is returned on the edge of the grid.
+func (c *Cell) Neighbour(s Side) *Cell {
+	d := s.delta()
+	return c.grid.Cell(c.X+d.X, c.Y+d.Y)
+}
+
+// Neighbours returns the adjacent cells reachable without passing a wall
+func (c *Cell) Neighbours() []*Cell {
+	list := make([]*Cell, 0, 4)
+	for _, s := range Sides {
+		if n := c.Neighbour(s); n != nil && c.Linked(s) {
+			list = append(list, n)
+		}
+	}
+	return list
+}
+
+// Link removes the wall on the given side, for both cells
+func (c *Cell) Link(s Side) {
+	c.setLink(s, true)
+}
+
+// Unlink places a wall on the given side, for both cells
+func (c *Cell) Unlink(s Side) {
+	c.setLink(s, false)
+}
+
+func (c *Cell) setLink(s Side, linked bool) {
+	if linked {
+		c.links |= s
+	} else {
+		c.links &^= s
+	}
+	if n := c.Neighbour(s); n != nil {
+		if linked {
+			n.links |= s.Opposite()
+		} else {
+			n.links &^= s.Opposite()
+		}
+	}
+	// the wall pixel changed so a token stored for it is no longer valid
+	delete(c.grid.extra, c.grid.Pixel(c).Add(s.delta()))
+}
+
+// Grid is a cell based view of the maze, cell (0,0) is the top left cell
+// inside the outer walls. It keeps track of pixels it can not describe so
+// converting back to a matrix gives the same matrix.
+type Grid struct {
+	Width  int
+	Height int
+	Cells  [][]*Cell // indexed as [y][x]
+	I      *MazeImageBuilder
+	frame  image.Rectangle             // outer walls in the matrix
+	size   image.Point                 // dimensions of the matrix
+	extra  map[image.Point]MatrixToken // pixels that differ from the rendered cells
+	costs  [][]uint8                   // cost layer of the matrix
+}
+
+// NewGrid creates the cell graph for the given matrix
+func NewGrid(m *MazeImageMatrix) *Grid {
+	frame := m.Frame()
+	g := &Grid{
+		Width:  frame.Dx() / 2,
+		Height: frame.Dy() / 2,
+		I:      m.I,
+		frame:  frame,
+		size:   image.Pt(len(m.M[0]), len(m.M)),
+		extra:  make(map[image.Point]MatrixToken),
+		costs:  copyCost(m.C),
+	}
+
+	if g.Width < 0 || g.Height < 0 {
+		g.Width, g.Height = 0, 0
+	}
+
+	g.Cells = make([][]*Cell, g.Height)
+	for y := 0; y < g.Height; y++ {
+		g.Cells[y] = make([]*Cell, g.Width)
+		for x := 0; x < g.Width; x++ {
+			c := &Cell{X: x, Y: y, grid: g}
+			p := g.Pixel(c)
+			c.Token = m.M[p.Y][p.X]
+			for _, s := range Sides {
+				if w := p.Add(s.delta()); !m.Has(w.X, w.Y, WALL) {
+					c.links |= s
+				}
+			}
+			g.Cells[y][x] = c
+		}
+	}
+
+	// remember every pixel the rendered grid would get wrong
+	rendered := g.render()
+	for y := range m.M {
+		for x := range m.M[y] {
+			if m.M[y][x] != rendered[y][x] {
+				g.extra[image.Pt(x, y)] = m.M[y][x]
+			}
+		}
+	}
+
+	return g
+}
+
+// Cell returns the cell at the given grid position or nil when outside
+func (g *Grid) Cell(x, y int) *Cell {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return nil
+	}
+	return g.Cells[y][x]
+}
+
+// Pixel returns the matrix position of a cell
+func (g *Grid) Pixel(c *Cell) image.Point {
+	return image.Pt(g.frame.Min.X+1+2*c.X, g.frame.Min.Y+1+2*c.Y)
+}
+
+// At returns the cell at the given matrix position or nil when the pixel
+// is not a cell (a wall, passage or outside the grid).
+func (g *Grid) At(x, y int) *Cell {
+	dx, dy := x-g.frame.Min.X-1, y-g.frame.Min.Y-1
+	if dx < 0 || dy < 0 || dx%2 != 0 || dy%2 != 0 {
+		return nil
+	}
+	return g.Cell(dx/2, dy/2)
+}
+
+// Matrix converts the grid back to a pixel matrix
+func (g *Grid) Matrix() *MazeImageMatrix {
+	matrix := g.render()
+	for p, t := range g.extra {
+		matrix[p.Y][p.X] = t
+	}
+	return &MazeImageMatrix{M: matrix, C: copyCost(g.costs), I: g.I}
+}
+
+// render draws the cells and links, pixels outside the outer walls are
+// filled the same as NewMazeImageMatrix does.
+func (g *Grid) render() [][]MatrixToken {
+	matrix := make([][]MatrixToken, g.size.Y)
+	for y := range matrix {
+		matrix[y] = make([]MatrixToken, g.size.X)
+		for x := range matrix[y] {
+			switch {
+			case x >= g.frame.Min.X && y >= g.frame.Min.Y && x <= g.frame.Max.X && y <= g.frame.Max.Y:
+				matrix[y][x] = WALL
+			case y == 0 || x == 0:
+				matrix[y][x] = BORDER
+			default:
+				matrix[y][x] = PATH
+			}
+		}
+	}
+	for _, row := range g.Cells {
+		for _, c := range row {
+			p := g.Pixel(c)
+			matrix[p.Y][p.X] = c.Token
+			for _, s := range Sides {
+				if c.Linked(s) {
+					w := p.Add(s.delta())
+					matrix[w.Y][w.X] = PATH
+				}
+			}
+		}
+	}
+	return matrix
+}
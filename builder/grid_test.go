@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGridRoundTrip(t *testing.T) {
+	tests := []struct {
+		generator string
+		height    int
+		width     int
+		costs     int
+	}{
+		{"backtracker", 1, 1, 0},
+		{"backtracker", 10, 10, 0},
+		{"prim", 7, 13, 0},
+		{"braid", 13, 7, 0},
+		{"braid", 10, 10, 5},
+	}
+	for _, test := range tests {
+		for seed := int64(1); seed <= 3; seed++ {
+			t.Run(fmt.Sprintf("%s/%dx%d/%d/%d", test.generator, test.height, test.width, test.costs, seed), func(t *testing.T) {
+				b := NewMazeImageBuilder(test.height, test.width)
+				b.SetCostRegions(test.costs)
+				m, err := b.Generate(test.generator, seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// tokens the cells can not describe are kept as well
+				m.M[1][1] |= START
+				m.M[len(m.M)-2][len(m.M[0])-2] |= END
+
+				grid := NewGrid(m)
+				if grid.Width != test.width || grid.Height != test.height {
+					t.Fatalf("expected a grid of %dx%d, got %dx%d", test.width, test.height, grid.Width, grid.Height)
+				}
+				back := grid.Matrix()
+				if !reflect.DeepEqual(back.M, m.M) {
+					t.Errorf("matrix changed converting to a grid and back\n%s\n%s", m, back)
+				}
+				if !reflect.DeepEqual(back.C, m.C) {
+					t.Error("cost layer changed converting to a grid and back")
+				}
+				if back.I != m.I {
+					t.Error("builder not kept converting to a grid and back")
+				}
+
+				// opening and closing a wall again gives the same matrix
+				cell := grid.Cell(0, 0)
+				for _, s := range []Side{EAST, SOUTH} {
+					if n := cell.Neighbour(s); n != nil && !cell.Linked(s) {
+						cell.Link(s)
+						cell.Unlink(s)
+					}
+				}
+				if !reflect.DeepEqual(grid.Matrix().M, m.M) {
+					t.Error("matrix changed after linking and unlinking a wall")
+				}
+			})
+		}
+	}
+}
+
+func TestGridCells(t *testing.T) {
+	m, err := NewMazeImageBuilder(4, 6).Generate("backtracker", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewGrid(m)
+	for y, row := range grid.Cells {
+		for x, c := range row {
+			if c.X != x || c.Y != y {
+				t.Fatalf("cell at %d,%d has position %d,%d", x, y, c.X, c.Y)
+			}
+			p := grid.Pixel(c)
+			if grid.At(p.X, p.Y) != c {
+				t.Errorf("pixel %s does not map back to cell %d,%d", p, x, y)
+			}
+			if !m.Has(p.X, p.Y, PATH) {
+				t.Errorf("cell %d,%d is not on a path pixel", x, y)
+			}
+			// the links match the pixels between the cells
+			for _, s := range Sides {
+				w := p.Add(s.delta())
+				if c.Linked(s) == m.Has(w.X, w.Y, WALL) {
+					t.Errorf("link %v of cell %d,%d does not match the matrix", s, x, y)
+				}
+				if n := c.Neighbour(s); n != nil && c.Linked(s) != n.Linked(s.Opposite()) {
+					t.Errorf("link %v of cell %d,%d is not mirrored by its neighbour", s, x, y)
+				}
+			}
+			// passages and walls between cells are not cells
+			if grid.At(p.X+1, p.Y) != nil || grid.At(p.X, p.Y+1) != nil {
+				t.Errorf("pixel next to cell %d,%d is a cell", x, y)
+			}
+		}
+	}
+	if grid.Cell(-1, 0) != nil || grid.Cell(grid.Width, 0) != nil || grid.Cell(0, grid.Height) != nil {
+		t.Error("expected no cells outside the grid")
+	}
+}
@@ -22,6 +22,7 @@ const (
 
 type MazeImageMatrix struct {
 	M [][]MatrixToken
+	C [][]uint8 // optional traversal cost per position, see Cost
 	I *MazeImageBuilder
 }
 
@@ -29,6 +30,48 @@ func (i MazeImageMatrix) Has(x, y int, t MatrixToken) bool {
 	return t == (t & i.M[y][x])
 }
 
+// Bounds returns the walkable area of the matrix, this excludes the border
+// so Max is the last usable position and not one past it.
+func (i MazeImageMatrix) Bounds() image.Rectangle {
+	return image.Rect(1, 1, len(i.M[0])-2, len(i.M)-2)
+}
+
+// Frame returns the rectangle spanned by the outer walls of the maze, cells
+// are found on the odd offsets from its minimum point.
+func (i MazeImageMatrix) Frame() image.Rectangle {
+	frame := image.Rectangle{Min: image.Pt(len(i.M[0]), len(i.M))}
+	for y, data := range i.M {
+		for x, token := range data {
+			if WALL != (WALL & token) {
+				continue
+			}
+			if x < frame.Min.X {
+				frame.Min.X = x
+			}
+			if y < frame.Min.Y {
+				frame.Min.Y = y
+			}
+			if x > frame.Max.X {
+				frame.Max.X = x
+			}
+			if y > frame.Max.Y {
+				frame.Max.Y = y
+			}
+		}
+	}
+	return frame
+}
+
+// Copy returns a deep copy of the matrix sharing the same builder
+func (i MazeImageMatrix) Copy() *MazeImageMatrix {
+	matrix := make([][]MatrixToken, len(i.M))
+	for y := range i.M {
+		matrix[y] = make([]MatrixToken, len(i.M[y]))
+		copy(matrix[y], i.M[y])
+	}
+	return &MazeImageMatrix{M: matrix, C: copyCost(i.C), I: i.I}
+}
+
 // String prints the the matrix to the stdout in visula way
 func (i MazeImageMatrix) String() string {
 	buff := new(bytes.Buffer)
@@ -101,7 +144,7 @@ func (i MazeImageMatrix) DrawImage() draw.Image {
 			case WALL:
 				draw.Draw(rgba, image.Rect(x, y, x+int(i.I.ratio), y+int(i.I.ratio)), &image.Uniform{i.I.wall_color}, image.ZP, draw.Src)
 			case PATH, BORDER:
-				draw.Draw(rgba, image.Rect(x, y, x+int(i.I.ratio), y+int(i.I.ratio)), &image.Uniform{i.I.path_color}, image.ZP, draw.Src)
+				draw.Draw(rgba, image.Rect(x, y, x+int(i.I.ratio), y+int(i.I.ratio)), &image.Uniform{i.costColor(x/int(i.I.ratio), y/int(i.I.ratio))}, image.ZP, draw.Src)
 			}
 		}
 	}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pbergman/maze/analysis"
+	"github.com/pbergman/maze/config"
+)
+
+// Analyze will fetch a maze and print its metrics as text or json
+func Analyze() {
+	matrix := fetch()
+	metrics, err := analysis.Measure(matrix)
+	checkError(err)
+
+	if config.Config.Json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		checkError(encoder.Encode(metrics))
+		return
+	}
+
+	fmt.Println(matrix)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Area\t%d\n", metrics.Area)
+	fmt.Fprintf(w, "Solution length\t%d\n", metrics.SolutionLength)
+	fmt.Fprintf(w, "Solution/area ratio\t%.3f\n", metrics.SolutionRatio)
+	fmt.Fprintf(w, "Turns on solution\t%d\n", metrics.Turns)
+	fmt.Fprintf(w, "Junctions on solution\t%d\n", metrics.Branching)
+	fmt.Fprintf(w, "Wrong turns\t%d\n", metrics.WrongTurns)
+	fmt.Fprintf(w, "Dead ends\t%d\n", metrics.DeadEnds)
+	fmt.Fprintf(w, "Dead end ratio\t%.3f\n", metrics.DeadEndRatio)
+	fmt.Fprintf(w, "Junctions\t%d\n", metrics.Junctions)
+	fmt.Fprintf(w, "Cycles\t%d\n", metrics.Cycles)
+	fmt.Fprintf(w, "River factor\t%.2f\n", metrics.River)
+	fmt.Fprintf(w, "Difficulty\t%.2f\n", metrics.Difficulty())
+	fmt.Fprintln(w, "Corridor lengths\t")
+	lengths := make([]int, 0, len(metrics.Corridors))
+	for length := range metrics.Corridors {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+	for _, length := range lengths {
+		fmt.Fprintf(w, "  %d\t%d\n", length, metrics.Corridors[length])
+	}
+	w.Flush()
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/solver"
+)
+
+// animate returns a context that redraws the maze in the terminal for every
+// event of the solver, waiting the given delay between the frames
+func animate(ctx context.Context, m *builder.MazeImageMatrix, delay time.Duration) context.Context {
+	recorder := solver.NewRecorder(m)
+	return solver.WithObserver(ctx, func(e solver.Event) {
+		recorder.Observe(e)
+		// move the cursor home and clear the screen before drawing
+		fmt.Printf("\033[H\033[2J%s%s at %s (%d events)\n", recorder, e.Kind, e.Position, recorder.Count())
+		time.Sleep(delay)
+	})
+}
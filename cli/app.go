@@ -1,32 +1,75 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/pbergman/maze/analysis"
 	"github.com/pbergman/maze/config"
 	"github.com/pbergman/maze/builder"
 	"github.com/pbergman/maze/solver"
 )
 
 func App() {
-	var wg sync.WaitGroup
+	process(fetch())
+}
+
+// fetch will build the configured maze
+func fetch() *builder.MazeImageMatrix {
 	log.Printf("Building maze with ratio %d, width %d, height: %d", config.Config.Scale, config.Config.Width, config.Config.Height)
 	maze := builder.NewMazeImageBuilder(config.Config.Height, config.Config.Width)
 	maze.SetRatio(config.Config.Scale)
 	log.Printf("Getting matrix and image: %s", maze)
-	matrix, err := maze.GetMatrix()
+	matrix, err := getMatrix(maze)
+	checkError(err)
+	return matrix
+}
+
+// process will print, solve and save the given maze
+func process(matrix *builder.MazeImageMatrix) {
+	var wg sync.WaitGroup
+	if !config.Config.Json {
+		fmt.Println(matrix)
+	}
+	s, err := getSolver(config.Config.Solver)
+	checkError(err)
+	log.Printf("Solving maze with %s", config.Config.Solver)
+	options, err := goalOptions(config.Config.Entrance, config.Config.Exit)
+	checkError(err)
+	walker, err := solver.NewWalker(matrix, options...)
+	checkError(err)
+	log.Printf("Solving from %v to %v", walker.GetStarts(), walker.GetEnds())
+	ctx, cancel := solver.BudgetContext(context.Background(), budget(), config.Config.Budget.Timeout)
+	defer cancel()
+	if config.Config.Animate > 0 {
+		ctx = animate(ctx, matrix, config.Config.Animate)
+	}
+	result, err := solver.RunGoals(ctx, s, matrix, walker.GetStarts(), walker.GetEnds())
+	checkError(err)
+	log.Printf("Done %s", result.Stats)
+	moves, err := formatMoves(matrix, result.Path, config.Config.Moves)
 	checkError(err)
-	fmt.Println(matrix)
-	log.Print("Solving maze")
-	start := time.Now()
-	walker := solver.NewWalker(matrix)
-	walker.Solve()
-	log.Printf("Done %s", time.Now().Sub(start))
-	fmt.Println(walker)
+	if config.Config.Json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		checkError(encoder.Encode(struct {
+			Solver string       `json:"solver"`
+			Stats  solver.Stats `json:"stats"`
+			Moves  string       `json:"moves,omitempty"`
+		}{config.Config.Solver, result.Stats, strings.Join(moves, ", ")}))
+	} else {
+		fmt.Println(result)
+		for _, line := range moves {
+			fmt.Println(line)
+		}
+	}
 	wg.Add(3)
 	go func() {
 		log.Printf("Saving maze: %s", config.Config.Files.Raw)
@@ -41,7 +84,7 @@ func App() {
 		o, err := os.Create(config.Config.Files.Solved)
 		checkError(err)
 		defer o.Close()
-		walker.ToFile(o)
+		result.ToFile(o)
 		wg.Done()
 	}()
 	go func() {
@@ -49,12 +92,154 @@ func App() {
 		o, err := os.Create(config.Config.Files.Animation)
 		checkError(err)
 		defer o.Close()
-		walker.CreateAnimationImage(o)
+		result.CreateAnimationImage(o)
 		wg.Done()
 	}()
 	wg.Wait()
 }
 
+// getMatrix fetches a maze, retrying till it meets the configured constraints
+func getMatrix(maze *builder.MazeImageBuilder) (*builder.MazeImageMatrix, error) {
+	placement, err := solver.ParsePlacement(config.Config.Placement)
+	if err != nil {
+		return nil, err
+	}
+	options, err := positionOptions()
+	if err != nil {
+		return nil, err
+	}
+	source := func() (*builder.MazeImageMatrix, error) {
+		matrix, err := maze.GetMatrix()
+		if err != nil {
+			return nil, err
+		}
+		if err := solver.Place(matrix, placement, nil); err != nil {
+			return nil, err
+		}
+		if config.Config.Costs > 0 {
+			matrix.PaintCostRegions(rand.New(rand.NewSource(time.Now().UnixNano())), config.Config.Costs)
+		}
+		if len(options) > 0 {
+			// the matrix is ours, mark the explicit positions on it
+			walker, err := solver.NewWalker(matrix, options...)
+			if err != nil {
+				return nil, err
+			}
+			walker.Mark()
+		}
+		return matrix, nil
+	}
+	constraints := analysis.Constraints{
+		MinSolution:  config.Config.Constraints.MinSolution,
+		MinDeadEnds:  config.Config.Constraints.MinDeadEnds,
+		MaxDeadEnds:  config.Config.Constraints.MaxDeadEnds,
+		MaxBranching: config.Config.Constraints.MaxBranching,
+	}
+	if constraints.IsZero() {
+		return source()
+	}
+	matrix, report, err := analysis.Generate(source, constraints, config.Config.Constraints.Attempts)
+	if report != nil {
+		log.Print(report)
+	}
+	if err == analysis.ErrConstraintsNotMet {
+		return matrix, nil
+	}
+	return matrix, err
+}
+
+// budget returns the solver budget set by the flags
+func budget() solver.Budget {
+	return solver.Budget{Steps: config.Config.Budget.Steps, Memory: config.Config.Budget.Memory}
+}
+
+// getSolver returns the solver by name configured with the solver flags
+func getSolver(name string) (solver.Solver, error) {
+	s, err := solver.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := s.(*solver.Parallel); ok && config.Config.Workers > 0 {
+		p.Workers = config.Config.Workers
+	}
+	if a, ok := s.(*solver.AStar); ok {
+		if a.Heuristic, err = solver.GetHeuristic(config.Config.Heuristic); err != nil {
+			return nil, err
+		}
+		if a.TieBreak, err = solver.ParseTieBreak(config.Config.TieBreak); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// formatMoves returns the path in the given move format, the directions
+// are returned a line per instruction and nothing for an empty format.
+func formatMoves(m *builder.MazeImageMatrix, path []solver.Position, format string) ([]string, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "moves":
+		return []string{solver.FormatMoves(solver.Moves(path))}, nil
+	case "runs":
+		return []string{solver.FormatRuns(solver.Moves(path))}, nil
+	case "cells":
+		return []string{solver.FormatMoves(solver.CellMoves(m, path))}, nil
+	case "cell-runs":
+		return []string{solver.FormatRuns(solver.CellMoves(m, path))}, nil
+	case "directions":
+		return solver.Instructions(solver.CellMoves(m, path), "cell"), nil
+	default:
+		return nil, fmt.Errorf("unknown move format %q, expected moves, runs, cells, cell-runs or directions", format)
+	}
+}
+
+// positionOptions returns the walker options for the explicit start and end flags
+func positionOptions() ([]solver.Option, error) {
+	options := make([]solver.Option, 0, 2)
+	if config.Config.Start != "" {
+		var x, y int
+		if _, err := fmt.Sscanf(config.Config.Start, "%d,%d", &x, &y); err != nil {
+			return nil, fmt.Errorf("invalid start %q, expected x,y", config.Config.Start)
+		}
+		options = append(options, solver.StartAt(x, y))
+	}
+	if config.Config.End != "" {
+		var x, y int
+		if _, err := fmt.Sscanf(config.Config.End, "%d,%d", &x, &y); err != nil {
+			return nil, fmt.Errorf("invalid end %q, expected x,y", config.Config.End)
+		}
+		options = append(options, solver.EndAt(x, y))
+	}
+	return options, nil
+}
+
+// goalOptions returns the walker options selecting an entrance and exit by
+// letter, the exit "nearest" selects the exit closest to the entrance and
+// without an entrance the closest pair of all entrances and exits.
+func goalOptions(entrance, exit string) ([]solver.Option, error) {
+	options := make([]solver.Option, 0, 2)
+	if entrance != "" {
+		i, err := solver.ParseLabel(entrance)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, solver.Entrance(i))
+	}
+	switch exit {
+	case "":
+	case "nearest":
+		options = append(options, solver.Closest())
+	default:
+		i, err := solver.ParseLabel(exit)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, solver.Exit(i))
+	}
+	return options, nil
+}
+
 func checkError(err error) {
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/config"
+	"github.com/pbergman/maze/solver"
+)
+
+// BenchRow holds the averages of one solver on the mazes of one generator and
+// size, the averages are taken over the successful runs only
+type BenchRow struct {
+	Generator  string        `json:"generator"`
+	Size       int           `json:"size"`
+	Solver     string        `json:"solver"`
+	Runs       int           `json:"runs"`
+	Failures   int           `json:"failures"` // solves without valid path, left out of the averages
+	Duration   time.Duration `json:"duration"`
+	Visited    float64       `json:"visited"`
+	PathLength float64       `json:"path_length"`
+}
+
+// Bench generates seeded mazes for every configured generator and size,
+// solves every maze with every solver and prints the averages
+func Bench() {
+	sizes, err := parseSizes(config.Config.Bench.Sizes)
+	checkError(err)
+	generators := builder.GeneratorNames()
+	if config.Config.Bench.Generators != "" {
+		generators = strings.Split(config.Config.Bench.Generators, ",")
+	}
+	format := config.Config.Bench.Format
+	if config.Config.Json {
+		format = "json"
+	}
+
+	rows := make([]*BenchRow, 0)
+	for _, generator := range generators {
+		for _, size := range sizes {
+			log.Printf("Benchmarking %d %s mazes of %dx%d", config.Config.Bench.Mazes, generator, size, size)
+			rows = append(rows, benchMazes(generator, size)...)
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		checkError(encoder.Encode(rows))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"generator", "size", "solver", "runs", "failures", "duration_ns", "visited", "path_length"})
+		for _, row := range rows {
+			w.Write([]string{
+				row.Generator,
+				strconv.Itoa(row.Size),
+				row.Solver,
+				strconv.Itoa(row.Runs),
+				strconv.Itoa(row.Failures),
+				strconv.FormatInt(int64(row.Duration), 10),
+				strconv.FormatFloat(row.Visited, 'f', 1, 64),
+				strconv.FormatFloat(row.PathLength, 'f', 1, 64),
+			})
+		}
+		w.Flush()
+		checkError(w.Error())
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "generator\tsize\tsolver\truns\tfailures\tduration\tvisited\tpath length\t")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%s\t%.1f\t%.1f\t\n", row.Generator, row.Size, row.Solver, row.Runs, row.Failures, row.Duration, row.Visited, row.PathLength)
+		}
+		w.Flush()
+	default:
+		log.Fatalf("Unknown format %q, expected table, csv or json", format)
+	}
+}
+
+// benchMazes solves the mazes of one generator and size with every solver
+func benchMazes(generator string, size int) []*BenchRow {
+	names := solver.Names()
+	rows := make([]*BenchRow, len(names))
+	for i, name := range names {
+		rows[i] = &BenchRow{Generator: generator, Size: size, Solver: name}
+	}
+
+	maze := builder.NewMazeImageBuilder(size, size)
+	maze.SetCostRegions(config.Config.Costs)
+	for n := 0; n < config.Config.Bench.Mazes; n++ {
+		matrix, err := maze.Generate(generator, config.Config.Bench.Seed+int64(n))
+		checkError(err)
+		walker, err := solver.NewWalker(matrix)
+		checkError(err)
+
+		for i, name := range names {
+			s, err := getSolver(name)
+			checkError(err)
+			ctx, cancel := solver.BudgetContext(context.Background(), budget(), config.Config.Budget.Timeout)
+			result, err := solver.Run(ctx, s, matrix, walker.GetStart(), walker.GetEnd())
+			cancel()
+			rows[i].Runs++
+			if err == nil {
+				err = solver.CheckPath(matrix, walker.GetStart(), walker.GetEnd(), result.Path).Err
+			}
+			if err != nil {
+				log.Printf("%s failed on %s maze %d: %s", name, generator, n, err)
+				rows[i].Failures++
+				continue
+			}
+			rows[i].Duration += result.Stats.Duration
+			rows[i].Visited += float64(result.Stats.Visited)
+			rows[i].PathLength += float64(result.Stats.PathLength)
+		}
+	}
+
+	for _, row := range rows {
+		if solved := row.Runs - row.Failures; solved > 0 {
+			row.Duration /= time.Duration(solved)
+			row.Visited /= float64(solved)
+			row.PathLength /= float64(solved)
+		}
+	}
+	return rows
+}
+
+// parseSizes parses a comma separated list of maze sizes
+func parseSizes(list string) ([]int, error) {
+	sizes := make([]int, 0)
+	for _, s := range strings.Split(list, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid size %q in %q", s, list)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"log"
+
+	"github.com/pbergman/maze/analysis"
+	"github.com/pbergman/maze/config"
+	"github.com/pbergman/maze/optimizer"
+)
+
+// Optimize will fetch a maze and evolve it to maximize the configured objective
+func Optimize() {
+	objective, err := optimizer.GetObjective(config.Config.Optimize.Objective)
+	checkError(err)
+	matrix := fetch()
+	metrics, err := analysis.Measure(matrix)
+	checkError(err)
+	log.Printf("Start: %s", metrics)
+	o := optimizer.NewOptimizer(objective)
+	o.Iterations = config.Config.Optimize.Iterations
+	o.Timeout = config.Config.Optimize.Timeout
+	o.Progress = 100
+	log.Printf("Optimizing %s for %d iterations (timeout: %s)", config.Config.Optimize.Objective, o.Iterations, o.Timeout)
+	matrix, metrics, err = o.Run(matrix)
+	checkError(err)
+	log.Printf("Result: %s", metrics)
+	process(matrix)
+}
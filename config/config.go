@@ -7,13 +7,53 @@ import (
 )
 
 type AppConfig struct {
-	Width    int
-	Height   int
-	Scale    uint
-	Files    struct{ Raw, Solved, Animation string }
-	Server   bool
-	Port     int
-	Template string
+	Width     int
+	Height    int
+	Scale     uint
+	Files     struct{ Raw, Solved, Animation string }
+	Server    bool
+	Port      int
+	Template  string
+	Command   string
+	Json      bool
+	Placement string
+	Start     string
+	Solver    string
+	Heuristic string
+	TieBreak  string
+	Workers   int
+	Animate   time.Duration
+	Moves     string
+	End       string
+	Entrance  string
+	Exit      string
+	Costs     int
+	// targets for constraint driven generation
+	Constraints struct {
+		MinSolution  int
+		MinDeadEnds  float64
+		MaxDeadEnds  float64
+		MaxBranching int
+		Attempts     int
+	}
+	// limits for a single solve, the server uses a timeout when none is set
+	Budget struct {
+		Steps   int
+		Memory  int
+		Timeout time.Duration
+	}
+	Bench struct {
+		Mazes      int
+		Sizes      string
+		Generators string
+		Format     string
+		Seed       int64
+	}
+	Optimize struct {
+		Objective  string
+		Iterations int
+		Timeout    time.Duration
+	}
 }
 
 var Config *AppConfig
@@ -29,5 +69,39 @@ func init() {
 	flag.StringVar(&Config.Files.Raw, "rf", "rmaze."+strconv.Itoa(int(time.Now().Unix()))+".gif", "File name to write maze to")
 	flag.StringVar(&Config.Files.Solved, "sf", "smaze."+strconv.Itoa(int(time.Now().Unix()))+".gif", "File name to write solved maze to")
 	flag.StringVar(&Config.Files.Animation, "af", "amaze."+strconv.Itoa(int(time.Now().Unix()))+".gif", "File name to write anmation maze to")
+	flag.IntVar(&Config.Constraints.MinSolution, "min-solution", 0, "Minimal solution length in pixels of generated maze")
+	flag.Float64Var(&Config.Constraints.MinDeadEnds, "min-dead-ends", 0, "Minimal dead end ratio (dead ends per cell) of generated maze")
+	flag.Float64Var(&Config.Constraints.MaxDeadEnds, "max-dead-ends", 0, "Maximal dead end ratio (dead ends per cell) of generated maze")
+	flag.IntVar(&Config.Constraints.MaxBranching, "max-branching", 0, "Maximal junctions on the solution of generated maze")
+	flag.IntVar(&Config.Constraints.Attempts, "attempts", 10, "Maximal mazes to generate when trying to meet the constraints")
+	flag.StringVar(&Config.Optimize.Objective, "objective", "difficulty", "Objective to maximize with the optimize command (length, wrong-turns, difficulty)")
+	flag.IntVar(&Config.Optimize.Iterations, "iterations", 1000, "Maximal iterations of the optimize command, 0 for no limit")
+	flag.DurationVar(&Config.Optimize.Timeout, "timeout", 0, "Maximal run time of the optimize command, 0 for no limit")
+	flag.IntVar(&Config.Bench.Mazes, "mazes", 5, "Number of mazes per generator and size for the bench command")
+	flag.StringVar(&Config.Bench.Sizes, "sizes", "10,25,50", "Comma separated maze sizes (cells per side) for the bench command")
+	flag.StringVar(&Config.Bench.Generators, "generators", "", "Comma separated generators for the bench command, empty for all (backtracker, braid, prim)")
+	flag.StringVar(&Config.Bench.Format, "format", "table", "Output format of the bench command (table, csv, json)")
+	flag.Int64Var(&Config.Bench.Seed, "seed", 1, "Seed of the first maze of the bench command")
+	flag.StringVar(&Config.Placement, "placement", "scan", "Placement of start and end (scan, longest, border, opposite)")
+	flag.StringVar(&Config.Solver, "solver", "walker", "Algorithm used to solve the maze, dijkstra takes the cost regions into account")
+	flag.StringVar(&Config.Heuristic, "heuristic", "manhattan", "Heuristic for the astar solver (manhattan, euclidean, chebyshev, zero)")
+	flag.StringVar(&Config.TieBreak, "tie", "closest", "Tie breaking for the astar solver (closest, farthest, newest, oldest)")
+	flag.IntVar(&Config.Workers, "workers", 0, "Number of goroutines for the parallel solver, 0 for one per cpu")
+	flag.IntVar(&Config.Budget.Steps, "max-steps", 0, "Maximal positions a solver may expand, 0 for no limit")
+	flag.IntVar(&Config.Budget.Memory, "max-memory", 0, "Maximal bytes a solver may use for the positions it keeps, 0 for no limit")
+	flag.DurationVar(&Config.Budget.Timeout, "solve-timeout", 0, "Maximal run time of a solver, 0 for no limit")
+	flag.StringVar(&Config.Moves, "moves", "", "Print the solution as moves (moves, runs, cells, cell-runs, directions), empty to disable")
+	flag.DurationVar(&Config.Animate, "animate", 0, "Animate solving in the terminal with the given delay between steps, 0 to disable")
+	flag.StringVar(&Config.Start, "start", "", "Explicit start position as x,y")
+	flag.StringVar(&Config.End, "end", "", "Explicit end position as x,y")
+	flag.StringVar(&Config.Entrance, "entrance", "", "Entrance to solve from by letter (A is the top left one), empty for the first")
+	flag.StringVar(&Config.Exit, "exit", "", "Exit to solve to by letter, nearest for the one closest to the entrance, empty for the first")
+	flag.IntVar(&Config.Costs, "cost-regions", 0, "Number of random regions with a higher traversal cost to paint on the maze")
+	flag.BoolVar(&Config.Json, "json", false, "Print command output as json")
 	flag.Parse()
+	// first argument is the command, flags may also follow it
+	if flag.NArg() > 0 {
+		Config.Command = flag.Arg(0)
+		flag.CommandLine.Parse(flag.Args()[1:])
+	}
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/solver"
 )
 
 var mazes map[int64]*builder.MazeImageMatrix
@@ -13,7 +14,10 @@ func init() {
 }
 
 func handelWebRequest(w http.ResponseWriter, r *http.Request) {
-	templates.Execute(w, struct {Mazes map[int64]*builder.MazeImageMatrix}{mazes})
+	templates.Execute(w, struct {
+		Mazes   map[int64]*builder.MazeImageMatrix
+		Solvers []string
+	}{mazes, solver.Names()})
 }
 
 func checkHttpError(err error, w http.ResponseWriter) {
@@ -1,22 +1,28 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"fmt"
 	"sync"
 	"log"
 	"encoding/binary"
+	"encoding/json"
 	"time"
 	"bytes"
 	"io/ioutil"
 
 	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/config"
 	"github.com/gorilla/websocket"
 	"github.com/pbergman/maze/solver"
 )
 
 var websockets *Websockets
 
+// solveTimeout is the run time limit of a solve requested by a client
+const solveTimeout = 30 * time.Second
+
 func init() {
 	http.HandleFunc("/ws", handleWebSocketRequest)
 	websockets = &Websockets{list: make([]*websocket.Conn, 0)}
@@ -93,7 +99,16 @@ func handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
 						maze.SetRatio(uint(ratio))
 						maze.SetWallColor(byte(data[7]),  byte(data[8]),  byte(data[9]))
 						maze.SetPathColor(byte(data[10]), byte(data[11]), byte(data[12]))
-						matrix, _ := maze.GetMatrix()
+						matrix, err := maze.GetMatrix()
+						if err != nil {
+							writeError(conn, err)
+							break
+						}
+						// marked before it is shared so solving never changes the tokens
+						if err := solver.Place(matrix, solver.SCAN, nil); err != nil {
+							writeError(conn, err)
+							break
+						}
 						mazes[id] = matrix
 						websockets.Broadcast(1, getTemplateList(w))
 					case 2:     // new update list
@@ -126,11 +141,63 @@ func handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
 						} else {
 							http.Error(w, fmt.Sprintf("No maze exist by id %d", int64(binary.BigEndian.Uint32(data[1:]))), 500)
 						}
-					case 4:
+					case 4, 5:  // solve maze, optionally followed by start x,y, end x,y (0 for default), entrance and exit index + 1 (0 for default, 255 for the nearest exit) and solver name, 5 streams the solver events
 						if m, ok := mazes[int64(binary.BigEndian.Uint32(data[1:]))]; ok {
 
-							walker := solver.NewWalker(m)
-							walker.Solve()
+							name := "walker"
+							var options []solver.Option
+							if len(data) >= 15 {
+								options = goalOptions(data[5:15])
+								if len(data) > 15 {
+									name = string(data[15:])
+								}
+							}
+
+							s, err := solver.Get(name)
+							if err != nil {
+								writeError(conn, err)
+								break
+							}
+							walker, err := solver.NewWalker(m, options...)
+							if err != nil {
+								writeError(conn, err)
+								break
+							}
+							// a solve never runs longer than solveTimeout when no timeout is set
+							timeout := config.Config.Budget.Timeout
+							if timeout <= 0 {
+								timeout = solveTimeout
+							}
+							ctx, cancel := solver.BudgetContext(context.Background(), solver.Budget{
+								Steps:  config.Config.Budget.Steps,
+								Memory: config.Config.Budget.Memory,
+							}, timeout)
+							var events chan solver.Event
+							done := make(chan struct{})
+							if data[0] == 5 {
+								events = make(chan solver.Event, eventBatch)
+								go streamEvents(conn, binary.BigEndian.Uint32(data[1:]), m.I.GetRatio(), events, done)
+								// drop the events when the solve is stopped so a client that
+								// stopped reading can not block the solver
+								solving := ctx
+								ctx = solver.WithObserver(ctx, func(e solver.Event) {
+									select {
+									case events <- e:
+									case <-solving.Done():
+									}
+								})
+							}
+							result, err := solver.RunGoals(ctx, s, m, walker.GetStarts(), walker.GetEnds())
+							cancel()
+							if events != nil {
+								// wait for the last events so the writes don't overlap
+								close(events)
+								<-done
+							}
+							if err != nil {
+								writeError(conn, err)
+								break
+							}
 
 							ratio := m.I.GetRatio()
 							buf := new(bytes.Buffer)
@@ -138,7 +205,12 @@ func handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
 							binary.Write(buf, binary.BigEndian, binary.BigEndian.Uint32(data[1:]))
 							binary.Write(buf, binary.BigEndian, uint16(ratio))
 
-							for _, t := range walker.GetResult().GetTraces() {
+							traces := result.Traces()
+							if events != nil {
+								// the client has drawn the events, only the route is left
+								traces = traces[len(traces)-len(result.Path):]
+							}
+							for _, t := range traces {
 								binary.Write(buf, binary.BigEndian, uint16(t.X*int(ratio)))
 								binary.Write(buf, binary.BigEndian, uint16(t.Y*int(ratio)))
 								if (solver.OK == (solver.OK & t.T)) {
@@ -148,12 +220,43 @@ func handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
 								}
 
 							}
-							err := conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+							err = conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+							checkHttpError(err, w)
+							directions := solver.Instructions(solver.CellMoves(m, result.Path), "cell")
+							err = writeStats(conn, binary.BigEndian.Uint32(data[1:]), result.Stats, directions)
 							checkHttpError(err, w)
 
 						} else {
 							http.Error(w, fmt.Sprintf("No maze exist by id %d", int64(binary.BigEndian.Uint32(data[1:]))), 500)
 						}
+					case 6:     // check a route, followed by start x,y, end x,y, entrance and exit as for 4 and the moves (U, D, L, R) from the start
+						if m, ok := mazes[int64(binary.BigEndian.Uint32(data[1:5]))]; ok {
+							if len(data) < 15 {
+								writeError(conn, fmt.Errorf("check message of %d bytes, expected at least 15", len(data)))
+								break
+							}
+							moves, err := solver.ParseMoves(string(data[15:]))
+							if err != nil {
+								writeError(conn, err)
+								break
+							}
+							walker, err := solver.NewWalker(m, goalOptions(data[5:15])...)
+							if err != nil {
+								writeError(conn, err)
+								break
+							}
+							// the moves may finish at any of the accepted exits
+							var verdict *solver.Verdict
+							for _, end := range walker.GetEnds() {
+								if v := solver.CheckMoves(m, walker.GetStart(), end, moves); verdict == nil || (v.Valid && !verdict.Valid) {
+									verdict = v
+								}
+							}
+							err = writeVerdict(conn, binary.BigEndian.Uint32(data[1:5]), m.I.GetRatio(), verdict)
+							checkHttpError(err, w)
+						} else {
+							http.Error(w, fmt.Sprintf("No maze exist by id %d", int64(binary.BigEndian.Uint32(data[1:5]))), 500)
+						}
 					}
 
 				default:
@@ -166,6 +269,116 @@ func handleWebSocketRequest(w http.ResponseWriter, r *http.Request) {
 		websockets.Remove(conn)
 }
 
+// eventBatch is the maximal number of events send in one message
+const eventBatch = 512
+
+// writeTimeout is the time a client gets to receive a batch of events
+const writeTimeout = 5 * time.Second
+
+// streamEvents sends the solver events to the client in batches of
+// [5][id:4][ratio:2] followed by [kind:1][x:2][y:2] for every event, the
+// positions are scaled by the ratio. Done is closed after the last batch.
+// When a write fails the remaining events are drained without sending.
+func streamEvents(conn *websocket.Conn, id uint32, ratio uint, events <-chan solver.Event, done chan<- struct{}) {
+	defer close(done)
+	defer conn.SetWriteDeadline(time.Time{})
+	buf := new(bytes.Buffer)
+	count := 0
+	var failed error
+	flush := func() {
+		if count > 0 && failed == nil {
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if failed = conn.WriteMessage(websocket.BinaryMessage, buf.Bytes()); failed != nil {
+				log.Printf("Stopped streaming events: %s", failed)
+			}
+		}
+		buf.Reset()
+		buf.Write([]byte{5}) // type id
+		binary.Write(buf, binary.BigEndian, id)
+		binary.Write(buf, binary.BigEndian, uint16(ratio))
+		count = 0
+	}
+	flush()
+	for e := range events {
+		buf.Write([]byte{byte(e.Kind)})
+		binary.Write(buf, binary.BigEndian, uint16(e.Position.X()*int(ratio)))
+		binary.Write(buf, binary.BigEndian, uint16(e.Position.Y()*int(ratio)))
+		// send a batch when full or when the solver is not producing events fast
+		if count++; count >= eventBatch || len(events) == 0 {
+			flush()
+		}
+	}
+	flush()
+}
+
+// writeStats sends the solver stats and the directions to follow the route
+// as [6][id:4][json]
+func writeStats(conn *websocket.Conn, id uint32, stats solver.Stats, directions []string) error {
+	buf := new(bytes.Buffer)
+	buf.Write([]byte{6}) // type id
+	binary.Write(buf, binary.BigEndian, id)
+	message := struct {
+		solver.Stats
+		Directions []string `json:"directions,omitempty"`
+	}{stats, directions}
+	if err := json.NewEncoder(buf).Encode(message); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+}
+
+// writeVerdict sends the outcome of a route check as [7][id:4][json], the
+// position where the route failed is scaled by the ratio.
+func writeVerdict(conn *websocket.Conn, id uint32, ratio uint, verdict *solver.Verdict) error {
+	buf := new(bytes.Buffer)
+	buf.Write([]byte{7}) // type id
+	binary.Write(buf, binary.BigEndian, id)
+	message := struct {
+		*solver.Verdict
+		X      int    `json:"x"`
+		Y      int    `json:"y"`
+		Reason string `json:"reason,omitempty"`
+	}{Verdict: verdict, X: verdict.At.X() * int(ratio), Y: verdict.At.Y() * int(ratio)}
+	if verdict.Err != nil {
+		message.Reason = verdict.Err.Error()
+	}
+	if err := json.NewEncoder(buf).Encode(message); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+}
+
+// goalOptions reads the walker options from [sx:2][sy:2][ex:2][ey:2]
+// [entrance:1][exit:1], a start or end of 0,0 keeps the default. The
+// entrance and exit are an index + 1 with 0 for the default and an exit
+// of 255 for the one nearest to the entrance.
+func goalOptions(data []byte) []solver.Option {
+	options := make([]solver.Option, 0, 4)
+	if data[8] != 0 {
+		options = append(options, solver.Entrance(int(data[8])-1))
+	}
+	switch data[9] {
+	case 0:
+	case 255:
+		options = append(options, solver.Closest())
+	default:
+		options = append(options, solver.Exit(int(data[9])-1))
+	}
+	if sx, sy := binary.BigEndian.Uint16(data[0:2]), binary.BigEndian.Uint16(data[2:4]); sx != 0 || sy != 0 {
+		options = append(options, solver.StartAt(int(sx), int(sy)))
+	}
+	if ex, ey := binary.BigEndian.Uint16(data[4:6]), binary.BigEndian.Uint16(data[6:8]); ex != 0 || ey != 0 {
+		options = append(options, solver.EndAt(int(ex), int(ey)))
+	}
+	return options
+}
+
+// writeError sends the error message to the client
+func writeError(conn *websocket.Conn, err error) {
+	m := []byte{4}
+	conn.WriteMessage(websocket.BinaryMessage, append(m, []byte(err.Error())...))
+}
+
 func getTemplateList(w http.ResponseWriter) []byte {
 	buffer := new(bytes.Buffer)
 	err := templates.ExecuteTemplate(buffer, "list-group", struct {Mazes map[int64]*builder.MazeImageMatrix}{mazes})
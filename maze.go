@@ -12,11 +12,20 @@ import (
 
 func main() {
 
-	if !config.Config.Server {
-		cli.App()
-	} else {
+	switch {
+	case config.Config.Server:
 		log.Printf("Server running on :%d", config.Config.Port)
 		log.Fatal(http.ListenAndServe(":"+strconv.Itoa(config.Config.Port), nil))
+	case config.Config.Command == "optimize":
+		cli.Optimize()
+	case config.Config.Command == "analyze":
+		cli.Analyze()
+	case config.Config.Command == "bench":
+		cli.Bench()
+	case config.Config.Command == "":
+		cli.App()
+	default:
+		log.Fatalf("Unknown command %q", config.Config.Command)
 	}
 }
 
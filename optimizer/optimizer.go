@@ -0,0 +1,162 @@
+package optimizer
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pbergman/maze/analysis"
+	"github.com/pbergman/maze/builder"
+	"github.com/pbergman/maze/solver"
+)
+
+// Objective returns the score of a maze, higher is better
+type Objective func(m *analysis.Metrics) float64
+
+var Objectives = map[string]Objective{
+	"length": func(m *analysis.Metrics) float64 {
+		return float64(m.SolutionLength)
+	},
+	"wrong-turns": func(m *analysis.Metrics) float64 {
+		return float64(m.WrongTurns)
+	},
+	"difficulty": func(m *analysis.Metrics) float64 {
+		return m.Difficulty()
+	},
+}
+
+// GetObjective returns the objective registered by the given name
+func GetObjective(name string) (Objective, error) {
+	if o, ok := Objectives[name]; ok {
+		return o, nil
+	}
+	names := make([]string, 0, len(Objectives))
+	for n := range Objectives {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown objective %q, available: %v", name, names)
+}
+
+// Optimizer evolves a perfect maze by hill climbing, every iteration opens
+// a random wall and closes another one on the loop that creates so the maze
+// stays perfect. Mutations that do not lower the score are kept.
+type Optimizer struct {
+	Objective  Objective
+	Iterations int           // maximal iterations, zero for no limit
+	Timeout    time.Duration // maximal run time, zero for no limit
+	Rand       *rand.Rand
+	Progress   int // log progress every n iterations, zero to disable
+}
+
+func NewOptimizer(o Objective) *Optimizer {
+	return &Optimizer{
+		Objective:  o,
+		Iterations: 1000,
+		Rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Run will optimize a copy of the given maze and returns the best found
+// maze with its metrics. Without iteration and time budget the copy is
+// returned as is.
+func (o *Optimizer) Run(m *builder.MazeImageMatrix) (*builder.MazeImageMatrix, *analysis.Metrics, error) {
+	best := m.Copy()
+	metrics, err := analysis.Measure(best)
+	if err != nil {
+		return nil, nil, err
+	}
+	score := o.Objective(metrics)
+	start := time.Now()
+
+	if o.Iterations <= 0 && o.Timeout <= 0 {
+		return best, metrics, nil
+	}
+
+	for i := 1; o.Iterations <= 0 || i <= o.Iterations; i++ {
+		if o.Timeout > 0 && time.Now().Sub(start) > o.Timeout {
+			log.Printf("Time budget of %s used after %d iterations", o.Timeout, i-1)
+			break
+		}
+		grid := builder.NewGrid(best)
+		if !o.mutate(best, grid) {
+			break
+		}
+		candidate := grid.Matrix()
+		cm, err := analysis.Measure(candidate)
+		if err != nil {
+			return nil, nil, err
+		}
+		if s := o.Objective(cm); s >= score {
+			if s > score {
+				log.Printf("Iteration %d: score %.2f -> %.2f", i, score, s)
+			}
+			best, metrics, score = candidate, cm, s
+		}
+		if o.Progress > 0 && i%o.Progress == 0 {
+			log.Printf("Iteration %d: score %.2f (%s)", i, score, time.Now().Sub(start))
+		}
+	}
+
+	return best, metrics, nil
+}
+
+// mutate opens a random inner wall between two cells of the grid made from
+// m and closes a random passage on the path that connected them before.
+func (o *Optimizer) mutate(m *builder.MazeImageMatrix, g *builder.Grid) bool {
+	type wall struct {
+		cell *builder.Cell
+		side builder.Side
+	}
+	walls := make([]wall, 0)
+
+	for _, row := range g.Cells {
+		for _, c := range row {
+			for _, s := range []builder.Side{builder.EAST, builder.SOUTH} {
+				if c.Neighbour(s) != nil && !c.Linked(s) {
+					walls = append(walls, wall{c, s})
+				}
+			}
+		}
+	}
+
+	if len(walls) == 0 {
+		return false
+	}
+
+	w := walls[o.Rand.Intn(len(walls))]
+	path := route(m, g, w.cell, w.cell.Neighbour(w.side))
+
+	if len(path) < 2 {
+		return false
+	}
+
+	i := o.Rand.Intn(len(path) - 1)
+	for _, s := range builder.Sides {
+		if path[i].Neighbour(s) == path[i+1] {
+			path[i].Unlink(s)
+		}
+	}
+	w.cell.Link(w.side)
+	return true
+}
+
+// route returns the cells on the shortest route from a to b in the matrix
+// the grid was made from
+func route(m *builder.MazeImageMatrix, g *builder.Grid, a, b *builder.Cell) []*builder.Cell {
+	from, to := g.Pixel(a), g.Pixel(b)
+	path, err := solver.Route(m, solver.NewPosition(from.X, from.Y), solver.NewPosition(to.X, to.Y))
+	if err != nil {
+		return nil
+	}
+	cells := make([]*builder.Cell, 0, len(path)/2+1)
+	for _, p := range path {
+		// the passages between the cells are skipped
+		if c := g.At(p.X(), p.Y()); c != nil {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
@@ -0,0 +1,208 @@
+package solver
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("astar", func() Solver { return NewAStar() })
+}
+
+// Heuristic estimates the distance between two positions
+type Heuristic func(a, b Position) float64
+
+var Heuristics = map[string]Heuristic{
+	"manhattan": func(a, b Position) float64 {
+		return math.Abs(float64(a.x-b.x)) + math.Abs(float64(a.y-b.y))
+	},
+	"euclidean": func(a, b Position) float64 {
+		return math.Hypot(float64(a.x-b.x), float64(a.y-b.y))
+	},
+	"chebyshev": func(a, b Position) float64 {
+		return math.Max(math.Abs(float64(a.x-b.x)), math.Abs(float64(a.y-b.y)))
+	},
+	"zero": func(a, b Position) float64 {
+		return 0
+	},
+}
+
+// GetHeuristic returns the heuristic registered by the given name
+func GetHeuristic(name string) (Heuristic, error) {
+	if h, ok := Heuristics[name]; ok {
+		return h, nil
+	}
+	names := make([]string, 0, len(Heuristics))
+	for n := range Heuristics {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("solver: unknown heuristic %q, available: %v", name, names)
+}
+
+// TieBreak decides which position is expanded first when the estimated
+// total distance is equal.
+type TieBreak uint8
+
+const (
+	CLOSEST  TieBreak = iota // lowest estimate to the end first
+	FARTHEST                 // highest estimate to the end first
+	NEWEST                   // last added first
+	OLDEST                   // first added first
+)
+
+func (t TieBreak) String() string {
+	switch t {
+	case CLOSEST:
+		return "closest"
+	case FARTHEST:
+		return "farthest"
+	case NEWEST:
+		return "newest"
+	case OLDEST:
+		return "oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTieBreak returns the tie break for the given name
+func ParseTieBreak(name string) (TieBreak, error) {
+	for _, t := range []TieBreak{CLOSEST, FARTHEST, NEWEST, OLDEST} {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return CLOSEST, fmt.Errorf("solver: unknown tie break %q", name)
+}
+
+// AStar expands the position with the lowest steps taken plus estimated
+// steps left, the path is the shortest as long as the heuristic never
+// overestimates (the euclidean and chebyshev distance on a grid don't).
+type AStar struct {
+	Heuristic Heuristic
+	TieBreak  TieBreak
+}
+
+func NewAStar() *AStar {
+	return &AStar{Heuristic: Heuristics["manhattan"], TieBreak: CLOSEST}
+}
+
+func (a *AStar) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	width := len(m.M[0])
+	parents := make([]int, width*len(m.M))
+	costs := make([]int, width*len(m.M))
+	closed := make([]bool, width*len(m.M))
+	open := &openSet{tie: a.TieBreak}
+	result := &Result{}
+	tracker := newTracker(ctx)
+
+	parents[start.y*width+start.x] = start.y*width + start.x + 1
+	heap.Push(open, &node{p: start, h: a.Heuristic(start, end)})
+
+	for open.Len() > 0 {
+		if open.Len() > result.Stats.MaxFrontier {
+			result.Stats.MaxFrontier = open.Len()
+		}
+		current := heap.Pop(open).(*node)
+		index := current.p.y*width + current.p.x
+		// positions can be queued more than once, only the cheapest counts
+		if closed[index] {
+			continue
+		}
+		closed[index] = true
+		result.Visited = append(result.Visited, current.p)
+		result.Stats.Expanded++
+		if err := tracker.step(len(result.Visited) + open.Len()); err != nil {
+			return result, err
+		}
+		tracker.visit(m, current.p)
+
+		if current.p == end {
+			tracker.emit(FOUND, end)
+			result.Path = path(parents, width, start, end)
+			result.Open = open.positions(closed, width)
+			return result, nil
+		}
+
+		for _, n := range Neighbours(m, current.p) {
+			next, g := n.y*width+n.x, current.g+m.Cost(n.x, n.y)
+			if closed[next] || (parents[next] != 0 && costs[next] <= g) {
+				continue
+			}
+			parents[next], costs[next] = index+1, g
+			heap.Push(open, &node{p: n, g: g, h: a.Heuristic(n, end)})
+		}
+	}
+
+	return result, ErrNoSolution
+}
+
+type node struct {
+	p   Position
+	g   int     // cost from start
+	h   float64 // estimated steps to end
+	seq int     // insert order
+}
+
+// openSet is a priority queue on the estimated total distance
+type openSet struct {
+	nodes []*node
+	tie   TieBreak
+	seq   int
+}
+
+func (o *openSet) Len() int { return len(o.nodes) }
+
+func (o *openSet) Less(i, j int) bool {
+	a, b := o.nodes[i], o.nodes[j]
+	if fa, fb := float64(a.g)+a.h, float64(b.g)+b.h; fa != fb {
+		return fa < fb
+	}
+	switch o.tie {
+	case CLOSEST:
+		if a.h != b.h {
+			return a.h < b.h
+		}
+	case FARTHEST:
+		if a.h != b.h {
+			return a.h > b.h
+		}
+	case NEWEST:
+		return a.seq > b.seq
+	}
+	return a.seq < b.seq
+}
+
+func (o *openSet) Swap(i, j int) { o.nodes[i], o.nodes[j] = o.nodes[j], o.nodes[i] }
+
+func (o *openSet) Push(x interface{}) {
+	n := x.(*node)
+	n.seq = o.seq
+	o.seq++
+	o.nodes = append(o.nodes, n)
+}
+
+func (o *openSet) Pop() interface{} {
+	n := o.nodes[len(o.nodes)-1]
+	o.nodes = o.nodes[:len(o.nodes)-1]
+	return n
+}
+
+// positions returns the queued positions that were not expanded
+func (o *openSet) positions(closed []bool, width int) []Position {
+	list := make([]Position, 0, len(o.nodes))
+	seen := make(map[Position]bool)
+	for _, n := range o.nodes {
+		if !closed[n.p.y*width+n.p.x] && !seen[n.p] {
+			seen[n.p] = true
+			list = append(list, n.p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("bfs", func() Solver { return BreadthFirst{} })
+}
+
+// BreadthFirst explores the maze level by level so the first route found
+// to the end is a shortest one, also in mazes with loops.
+type BreadthFirst struct{}
+
+func (BreadthFirst) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	width := len(m.M[0])
+	// parent index + 1 for every position, 0 means not visited yet
+	parents := make([]int, width*len(m.M))
+	parents[start.y*width+start.x] = start.y*width + start.x + 1
+	result := &Result{Visited: []Position{start}}
+	tracker := newTracker(ctx)
+
+	for i := 0; i < len(result.Visited); i++ {
+		if err := tracker.step(len(result.Visited)); err != nil {
+			return result, err
+		}
+		if queued := len(result.Visited) - i; queued > result.Stats.MaxFrontier {
+			result.Stats.MaxFrontier = queued
+		}
+		result.Stats.Expanded++
+		current := result.Visited[i]
+		tracker.visit(m, current)
+		if current == end {
+			tracker.emit(FOUND, end)
+			result.Path = path(parents, width, start, end)
+			return result, nil
+		}
+		for _, n := range Neighbours(m, current) {
+			if index := n.y*width + n.x; parents[index] == 0 {
+				parents[index] = current.y*width + current.x + 1
+				result.Visited = append(result.Visited, n)
+			}
+		}
+	}
+
+	return result, ErrNoSolution
+}
+
+// path follows the parent indexes back from end to start and returns the
+// positions in walking order.
+func path(parents []int, width int, start, end Position) []Position {
+	list := []Position{end}
+	for current := end; current != start; {
+		index := parents[current.y*width+current.x] - 1
+		current = Position{index % width, index / width}
+		list = append(list, current)
+	}
+	for a, b := 0, len(list)-1; a < b; a, b = a+1, b-1 {
+		list[a], list[b] = list[b], list[a]
+	}
+	return list
+}
@@ -0,0 +1,96 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("bidirectional", func() Solver { return Bidirectional{} })
+}
+
+// Bidirectional runs a breadth first search from the start and the end at
+// the same time, every round the smallest frontier is expanded one level.
+// The searches meet halfway so on large mazes far less positions are
+// visited than with BreadthFirst, while the route is still a shortest one.
+type Bidirectional struct{}
+
+func (Bidirectional) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	width := len(m.M[0])
+	result := &Result{}
+	tracker := newTracker(ctx)
+	// per side the parent index + 1 and the distance to its origin
+	parents := [2][]int{make([]int, width*len(m.M)), make([]int, width*len(m.M))}
+	distances := [2][]int{make([]int, width*len(m.M)), make([]int, width*len(m.M))}
+	frontiers := [2][]Position{{start}, {end}}
+	parents[0][start.y*width+start.x] = start.y*width + start.x + 1
+	parents[1][end.y*width+end.x] = end.y*width + end.x + 1
+
+	if start == end {
+		result.Path = []Position{start}
+		return result, nil
+	}
+
+	// the meeting is the step between a position reached from the start and
+	// one reached from the end
+	var meet [2]Position
+	found, best := false, 0
+
+	for !found && len(frontiers[0]) > 0 && len(frontiers[1]) > 0 {
+		side := 0
+		if len(frontiers[1]) < len(frontiers[0]) {
+			side = 1
+		}
+		if size := len(frontiers[0]) + len(frontiers[1]); size > result.Stats.MaxFrontier {
+			result.Stats.MaxFrontier = size
+		}
+		level := frontiers[side]
+		frontiers[side] = nil
+		for _, current := range level {
+			if err := tracker.step(len(result.Visited) + len(frontiers[0]) + len(frontiers[1])); err != nil {
+				return result, err
+			}
+			index := current.y*width + current.x
+			tracker.visit(m, current)
+			result.Visited = append(result.Visited, current)
+			result.Stats.Expanded++
+			if side == 1 {
+				result.Backward = append(result.Backward, current)
+			}
+			for _, n := range Neighbours(m, current) {
+				next := n.y*width + n.x
+				// the other side reached this position, the complete level is
+				// expanded before stopping so the shortest meeting point is used
+				if parents[1-side][next] != 0 {
+					if distance := distances[side][index] + 1 + distances[1-side][next]; !found || distance < best {
+						found, best = true, distance
+						meet[side], meet[1-side] = current, n
+					}
+					continue
+				}
+				if parents[side][next] == 0 {
+					parents[side][next] = index + 1
+					distances[side][next] = distances[side][index] + 1
+					frontiers[side] = append(frontiers[side], n)
+				}
+			}
+		}
+	}
+
+	result.Open = append(frontiers[0], frontiers[1]...)
+
+	if !found {
+		return result, ErrNoSolution
+	}
+
+	tracker.emit(FOUND, meet[0])
+
+	// join the route from the start with the reversed route from the end
+	result.Path = path(parents[0], width, start, meet[0])
+	back := path(parents[1], width, end, meet[1])
+	for i := len(back) - 1; i >= 0; i-- {
+		result.Path = append(result.Path, back[i])
+	}
+	return result, nil
+}
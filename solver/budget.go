@@ -0,0 +1,76 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+var ErrBudgetExceeded = errors.New("solver: budget exceeded")
+
+// Budget limits the work a solver may do, a zero value for a field means
+// no limit.
+type Budget struct {
+	Steps  int // positions a solver may expand
+	Memory int // bytes a solver may use for the positions it keeps
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a context that limits the solvers using it
+func WithBudget(ctx context.Context, b Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// GetBudget returns the budget of the context
+func GetBudget(ctx context.Context) Budget {
+	b, _ := ctx.Value(budgetKey{}).(Budget)
+	return b
+}
+
+// BudgetContext returns a context that limits the solvers using it by the
+// budget and stops them after the timeout, zero for no timeout
+func BudgetContext(ctx context.Context, b Budget, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx = WithBudget(ctx, b)
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// tracker counts the steps of a solver and stops it when the context is
+// done or the budget is used up, it also emits the events to the observer
+// of the context. It is safe to use from multiple goroutines.
+type tracker struct {
+	ctx      context.Context
+	budget   Budget
+	steps    int64
+	observer Observer
+}
+
+func newTracker(ctx context.Context) *tracker {
+	o, _ := ctx.Value(observerKey{}).(Observer)
+	return &tracker{ctx: ctx, budget: GetBudget(ctx), observer: o}
+}
+
+// step is called for every expanded position with the number of positions
+// the solver is keeping.
+func (t *tracker) step(stored int) error {
+	steps := atomic.AddInt64(&t.steps, 1)
+	// checking the context takes a lock so it is not done every step
+	if steps%1024 == 1 {
+		if err := t.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if t.budget.Steps > 0 && steps > int64(t.budget.Steps) {
+		return fmt.Errorf("%w: more than %d steps", ErrBudgetExceeded, t.budget.Steps)
+	}
+	if size := stored * int(unsafe.Sizeof(Position{})); t.budget.Memory > 0 && size > t.budget.Memory {
+		return fmt.Errorf("%w: %d bytes used, %d allowed", ErrBudgetExceeded, size, t.budget.Memory)
+	}
+	return nil
+}
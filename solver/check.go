@@ -0,0 +1,86 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var (
+	ErrNotStart    = errors.New("route does not begin at the start")
+	ErrNotEnd      = errors.New("route does not finish at the end")
+	ErrNotAdjacent = errors.New("position is not next to the previous one")
+)
+
+// Verdict is the outcome of checking a candidate route
+type Verdict struct {
+	Valid    bool     `json:"valid"`
+	Optimal  bool     `json:"optimal"`  // valid and no route from start to end has fewer steps, the cost layer is not used
+	Step     int      `json:"step"`     // index of the first invalid position, -1 for a valid route
+	At       Position `json:"-"`        // first invalid position, where the route hits a wall or leaves the maze
+	Length   int      `json:"length"`   // positions on the candidate route
+	Shortest int      `json:"shortest"` // positions on a shortest route, 0 when there is none
+	Err      error    `json:"-"`        // why the route is invalid, wraps one of the position errors
+}
+
+func (v Verdict) String() string {
+	switch {
+	case v.Optimal:
+		return fmt.Sprintf("valid and optimal route of %d positions", v.Length)
+	case v.Valid:
+		return fmt.Sprintf("valid route of %d positions, the shortest has %d", v.Length, v.Shortest)
+	default:
+		return fmt.Sprintf("invalid route at step %d: %s", v.Step, v.Err)
+	}
+}
+
+// CheckPath checks if the path is a walk from start to end over path
+// positions, every position has to be next to the previous one. Positions
+// may be visited more than once.
+func CheckPath(m *builder.MazeImageMatrix, start, end Position, path []Position) *Verdict {
+	v := &Verdict{Step: -1, Length: len(path)}
+	fail := func(step int, p Position, err error) *Verdict {
+		v.Step, v.At, v.Err = step, p, err
+		return v
+	}
+	if err := Validate(m, start); err != nil {
+		return fail(0, start, fmt.Errorf("start %w", err))
+	}
+	_, list := distances(m, start)
+	if steps, ok := list[end]; ok {
+		v.Shortest = steps + 1
+	}
+	if len(path) == 0 {
+		return fail(0, start, ErrNotStart)
+	}
+	for i, p := range path {
+		if err := Validate(m, p); err != nil {
+			return fail(i, p, err)
+		}
+		if i == 0 && p != start {
+			return fail(i, p, fmt.Errorf("%s: %w %s", p, ErrNotStart, start))
+		}
+		if i > 0 && distance(path[i-1], p) != 1 {
+			return fail(i, p, fmt.Errorf("%s: %w %s", p, ErrNotAdjacent, path[i-1]))
+		}
+	}
+	if last := path[len(path)-1]; last != end {
+		return fail(len(path)-1, last, fmt.Errorf("%s: %w %s", last, ErrNotEnd, end))
+	}
+	v.Valid = true
+	v.Optimal = v.Length == v.Shortest
+	return v
+}
+
+// CheckMoves walks the moves from start and checks the walk as CheckPath,
+// the step of the verdict is the number of the move that failed counting
+// from 1, or 0 when the start itself is invalid.
+func CheckMoves(m *builder.MazeImageMatrix, start, end Position, moves []Direction) *Verdict {
+	path := make([]Position, 0, len(moves)+1)
+	path = append(path, start)
+	for _, d := range moves {
+		path = append(path, path[len(path)-1].step(d))
+	}
+	return CheckPath(m, start, end, path)
+}
@@ -0,0 +1,86 @@
+package solver
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkMaze has a loop so both ways around are a shortest route
+var checkMaze = []string{
+	"#######",
+	"#S    #",
+	"# ### #",
+	"#    E#",
+	"#######",
+}
+
+func TestCheckMoves(t *testing.T) {
+	tests := []struct {
+		name    string
+		moves   string
+		valid   bool
+		optimal bool
+		step    int
+		err     error
+	}{
+		{"optimal", "R4 D2", true, true, -1, nil},
+		{"optimal other way", "D2 R4", true, true, -1, nil},
+		{"detour", "R4 D2 L R", true, false, -1, nil},
+		{"around the loop", "D2 R4 U2 L4 D2 R4", true, false, -1, nil},
+		{"first move", "U R4 D2", false, false, 1, ErrWall},
+		{"first move left", "L", false, false, 1, ErrWall},
+		{"last move", "R4 D3", false, false, 7, ErrWall},
+		{"through a wall", "R D", false, false, 2, ErrWall},
+		{"short of the end", "R4 D", false, false, 5, ErrNotEnd},
+		{"no moves", "", false, false, 0, ErrNotEnd},
+	}
+	m, start, end := drawnMaze(checkMaze...)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moves, err := ParseMoves(test.moves)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v := CheckMoves(m, start, end, moves)
+			if v.Valid != test.valid || v.Optimal != test.optimal || v.Step != test.step {
+				t.Errorf("expected valid %t, optimal %t at step %d, got %s (%+v)", test.valid, test.optimal, test.step, v, *v)
+			}
+			if !errors.Is(v.Err, test.err) {
+				t.Errorf("expected error %v, got %v", test.err, v.Err)
+			}
+			if v.Shortest != 7 {
+				t.Errorf("expected a shortest route of 7 positions, got %d", v.Shortest)
+			}
+		})
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	m, start, end := drawnMaze(checkMaze...)
+	tests := []struct {
+		name  string
+		start Position
+		path  []Position
+		step  int
+		err   error
+	}{
+		{"empty", start, nil, 0, ErrNotStart},
+		{"other start", start, []Position{NewPosition(3, 2), NewPosition(4, 2)}, 0, ErrNotStart},
+		{"jump", start, []Position{start, NewPosition(4, 2)}, 1, ErrNotAdjacent},
+		{"start on a wall", NewPosition(1, 1), []Position{NewPosition(1, 1)}, 0, ErrWall},
+		{"start before the matrix", NewPosition(-1, -1), []Position{NewPosition(-1, -1)}, 0, ErrOutOfBounds},
+		{"start after the matrix", NewPosition(100, 2), []Position{NewPosition(100, 2)}, 0, ErrOutOfBounds},
+		{"leaving the matrix", start, []Position{start, NewPosition(2, 1), NewPosition(2, 0), NewPosition(2, -1)}, 1, ErrWall},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := CheckPath(m, test.start, end, test.path)
+			if v.Valid || v.Step != test.step {
+				t.Errorf("expected invalid at step %d, got %s", test.step, v)
+			}
+			if !errors.Is(v.Err, test.err) {
+				t.Errorf("expected error %v, got %v", test.err, v.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("dijkstra", func() Solver { return Dijkstra{} })
+}
+
+// Dijkstra finds the route with the lowest total cost, see MazeImageMatrix.Cost.
+// On a maze without cost layer this gives the same route length as BreadthFirst.
+type Dijkstra struct{}
+
+func (Dijkstra) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	// without estimate A* expands on cost only, oldest first keeps it stable
+	return (&AStar{Heuristic: Heuristics["zero"], TieBreak: OLDEST}).Solve(ctx, m, start, end)
+}
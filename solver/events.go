@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/pbergman/maze/builder"
+)
+
+type EventKind uint8
+
+const (
+	VISIT     EventKind = iota + 1 // position is explored
+	BACKTRACK                      // solver went back to the position
+	JUNCTION                       // explored position has more than two ways
+	DEADEND                        // explored position has one way, or is filled
+	FOUND                          // the end is reached
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case VISIT:
+		return "visit"
+	case BACKTRACK:
+		return "backtrack"
+	case JUNCTION:
+		return "junction"
+	case DEADEND:
+		return "dead end"
+	case FOUND:
+		return "found"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is emitted by a solver while it is solving
+type Event struct {
+	Kind     EventKind
+	Position Position
+}
+
+// Observer receives the events of a solver, the parallel solver calls it
+// from multiple goroutines.
+type Observer func(e Event)
+
+type observerKey struct{}
+
+// WithObserver returns a context that makes the solvers using it emit their
+// events to the observer
+func WithObserver(ctx context.Context, o Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, o)
+}
+
+// emit sends an event to the observer when there is one
+func (t *tracker) emit(kind EventKind, p Position) {
+	if t.observer != nil {
+		t.observer(Event{kind, p})
+	}
+}
+
+// visit emits a visit of the position followed by a junction or dead end
+// event based on the ways it has
+func (t *tracker) visit(m *builder.MazeImageMatrix, p Position) {
+	if t.observer == nil {
+		return
+	}
+	t.emit(VISIT, p)
+	switch n := len(Neighbours(m, p)); true {
+	case n >= 3:
+		t.emit(JUNCTION, p)
+	case n == 1:
+		t.emit(DEADEND, p)
+	}
+}
+
+// Recorder keeps the last event of every position so the progress of a
+// solver can be printed, Observe can be used as Observer.
+type Recorder struct {
+	m      *builder.MazeImageMatrix
+	events map[Position]EventKind
+	count  int
+	lock   sync.Mutex
+}
+
+func NewRecorder(m *builder.MazeImageMatrix) *Recorder {
+	return &Recorder{m: m, events: make(map[Position]EventKind)}
+}
+
+func (r *Recorder) Observe(e Event) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events[e.Position] = e.Kind
+	r.count++
+}
+
+// Count returns the number of events observed
+func (r *Recorder) Count() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.count
+}
+
+// String prints the matrix with a character for the last event on a position
+func (r *Recorder) String() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	buff := new(bytes.Buffer)
+	for y, data := range r.m.M {
+		for x, token := range data {
+			switch r.events[Position{x, y}] {
+			case VISIT:
+				buff.Write([]byte{'.'})
+			case BACKTRACK:
+				buff.Write([]byte{'<'})
+			case JUNCTION:
+				buff.Write([]byte{'+'})
+			case DEADEND:
+				buff.Write([]byte{'x'})
+			case FOUND:
+				buff.Write([]byte{'*'})
+			default:
+				writeToken(buff, token)
+			}
+		}
+		buff.Write([]byte{'\n'})
+	}
+	return string(buff.Bytes())
+}
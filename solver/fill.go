@@ -0,0 +1,234 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("dead-end", func() Solver { return DeadEndFilling{} })
+	Register("cul-de-sac", func() Solver { return CulDeSacFilling{} })
+}
+
+// DeadEndFilling fills every dead end until none are left, in a perfect maze
+// only the solution is left unfilled. The filled positions are the visited
+// positions in the order they were filled, the unfilled positions are the
+// remaining ones.
+type DeadEndFilling struct{}
+
+func (DeadEndFilling) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	f := newFiller(ctx, m, start, end)
+	f.deadEnds()
+	return f.result()
+}
+
+// CulDeSacFilling fills the dead ends and after that the parts of a braided
+// maze that hang on a single passage without holding the start or end, like
+// a loop with one way in. What is left holds every route without detours.
+type CulDeSacFilling struct{}
+
+func (CulDeSacFilling) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	f := newFiller(ctx, m, start, end)
+	f.deadEnds()
+	if f.err == nil {
+		f.culDeSacs()
+	}
+	return f.result()
+}
+
+type filler struct {
+	m          *builder.MazeImageMatrix
+	start, end Position
+	width      int
+	filled     []bool
+	visited    []Position // filled positions in order
+	tracker    *tracker
+	err        error // first error of the tracker, filling stops after it
+}
+
+func newFiller(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) *filler {
+	return &filler{m: m, start: start, end: end, width: len(m.M[0]), filled: make([]bool, len(m.M[0])*len(m.M)), tracker: newTracker(ctx)}
+}
+
+// open returns the unfilled neighbours of the position
+func (f *filler) open(p Position) []Position {
+	list := Neighbours(f.m, p)
+	for i := 0; i < len(list); i++ {
+		if f.filled[list[i].y*f.width+list[i].x] {
+			list = append(list[:i], list[i+1:]...)
+			i--
+		}
+	}
+	return list
+}
+
+func (f *filler) fill(p Position) {
+	f.filled[p.y*f.width+p.x] = true
+	f.visited = append(f.visited, p)
+	f.tracker.emit(DEADEND, p)
+	if f.err == nil {
+		f.err = f.tracker.step(len(f.visited))
+	}
+}
+
+// isDeadEnd checks if the position is an unfilled path with at most one way out
+func (f *filler) isDeadEnd(p Position) bool {
+	return p != f.start && p != f.end && !f.filled[p.y*f.width+p.x] && len(f.open(p)) <= 1
+}
+
+// deadEnds fills dead ends, filling one can turn its neighbour into a dead
+// end so that is checked till the corridor reaches a junction.
+func (f *filler) deadEnds() {
+	queue := make([]Position, 0)
+	bounds := f.m.Bounds()
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if p := (Position{x, y}); f.m.Has(x, y, builder.PATH) && f.isDeadEnd(p) {
+				queue = append(queue, p)
+			}
+		}
+	}
+	for len(queue) > 0 && f.err == nil {
+		p := queue[0]
+		queue = queue[1:]
+		if !f.isDeadEnd(p) {
+			continue
+		}
+		f.fill(p)
+		for _, n := range f.open(p) {
+			if f.isDeadEnd(n) {
+				queue = append(queue, n)
+			}
+		}
+	}
+}
+
+// culDeSacs fills everything behind a bridge, a passage that is the only
+// connection of a part of the maze, when that part does not hold the end.
+// Bridges are found with a depth first search from the start, positions
+// it can not reach are filled as well.
+func (f *filler) culDeSacs() {
+	discovered := make([]int, len(f.filled)) // discover order + 1, 0 is not discovered
+	low := make([]int, len(f.filled))
+	counter := 0
+	cut := make([][2]Position, 0) // bridges as the root of the part to fill and its parent
+
+	// the search keeps its own stack as it goes as deep as the longest
+	// corridor, which can be most of a large maze
+	type frame struct {
+		p, parent Position
+		ways      []Position
+		next      int  // index in ways to look at next
+		hasEnd    bool // the end is in the part below the position
+	}
+	push := func(stack []*frame, p, parent Position) []*frame {
+		counter++
+		discovered[p.y*f.width+p.x], low[p.y*f.width+p.x] = counter, counter
+		return append(stack, &frame{p: p, parent: parent, ways: f.open(p), hasEnd: p == f.end})
+	}
+	stack := push(nil, f.start, Position{-1, -1})
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		index := top.p.y*f.width + top.p.x
+		if top.next < len(top.ways) {
+			n := top.ways[top.next]
+			top.next++
+			if next := n.y*f.width + n.x; discovered[next] == 0 {
+				stack = push(stack, n, top.p)
+			} else if n != top.parent && discovered[next] < low[index] {
+				low[index] = discovered[next]
+			}
+			continue
+		}
+		// all ways are searched, report back to the parent
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			break
+		}
+		parent := stack[len(stack)-1]
+		up := parent.p.y*f.width + parent.p.x
+		if low[index] < low[up] {
+			low[up] = low[index]
+		}
+		if low[index] > discovered[up] && !top.hasEnd {
+			cut = append(cut, [2]Position{top.p, parent.p})
+		}
+		parent.hasEnd = parent.hasEnd || top.hasEnd
+	}
+
+	// the bridges are found innermost first, an outer part fills the inner ones
+	for _, bridge := range cut {
+		root := bridge[0]
+		if f.err != nil {
+			return
+		}
+		if f.filled[root.y*f.width+root.x] {
+			continue
+		}
+		// with the root filled the rest of the part can only be reached
+		// from the root, the parent is not part of it
+		f.fill(root)
+		queue := make([]Position, 0)
+		for _, n := range f.open(root) {
+			if n != bridge[1] {
+				f.fill(n)
+				queue = append(queue, n)
+			}
+		}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for _, n := range f.open(p) {
+				f.fill(n)
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	bounds := f.m.Bounds()
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if index := y*f.width + x; f.m.Has(x, y, builder.PATH) && discovered[index] == 0 && !f.filled[index] {
+				f.fill(Position{x, y})
+			}
+		}
+	}
+}
+
+// result returns the fill steps, the unfilled positions and the shortest
+// route through them.
+func (f *filler) result() (*Result, error) {
+	result := &Result{Visited: f.visited, Stats: Stats{Expanded: len(f.visited)}}
+	if f.err != nil {
+		return result, f.err
+	}
+	bounds := f.m.Bounds()
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if f.m.Has(x, y, builder.PATH) && !f.filled[y*f.width+x] {
+				result.Remaining = append(result.Remaining, Position{x, y})
+			}
+		}
+	}
+
+	parents := make([]int, len(f.filled))
+	parents[f.start.y*f.width+f.start.x] = f.start.y*f.width + f.start.x + 1
+	queue := []Position{f.start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p == f.end {
+			result.Path = path(parents, f.width, f.start, f.end)
+			f.tracker.emit(FOUND, f.end)
+			return result, nil
+		}
+		for _, n := range f.open(p) {
+			if index := n.y*f.width + n.x; parents[index] == 0 {
+				parents[index] = p.y*f.width + p.x + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return result, ErrNoSolution
+}
@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// loopMaze has loops around the start and end and a loop below them that
+// hangs on the single passage in the middle, a cul-de-sac
+var loopMaze = []string{
+	"#########",
+	"#S  #  E#",
+	"# # # # #",
+	"#       #",
+	"#### ####",
+	"#       #",
+	"# ##### #",
+	"#       #",
+	"#########",
+}
+
+func TestFilling(t *testing.T) {
+	tests := []struct {
+		solver    Solver
+		remaining func(p Position) bool // positions expected to be left
+	}{
+		// the loops have no dead ends so nothing is filled
+		{DeadEndFilling{}, func(p Position) bool { return true }},
+		// the bridge and the loop behind it are filled, the loops around
+		// start and end are routes and are kept
+		{CulDeSacFilling{}, func(p Position) bool { return p.y <= 4 }},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T", test.solver), func(t *testing.T) {
+			m, start, end := drawnMaze(loopMaze...)
+			result, err := test.solver.Solve(context.Background(), m, start, end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			left := make(map[Position]bool, len(result.Remaining))
+			for _, p := range result.Remaining {
+				left[p] = true
+			}
+			for y := range m.M {
+				for x := range m.M[y] {
+					p := NewPosition(x, y)
+					if y == 0 || x == 0 || y == len(m.M)-1 || x == len(m.M[y])-1 || !passable(m, p) {
+						continue
+					}
+					if want := test.remaining(p); left[p] != want {
+						t.Errorf("expected %s left %t, got %t", p, want, left[p])
+					}
+				}
+			}
+			if v := CheckPath(m, start, end, result.Path); !v.Optimal {
+				t.Errorf("expected a shortest route, got %s", v)
+			}
+		})
+	}
+}
+
+func TestCulDeSacBridges(t *testing.T) {
+	tests := []struct {
+		name   string
+		maze   []string
+		filled int
+	}{
+		// a loop with one way in is filled with its way in
+		{"loop behind a bridge", loopMaze, 16 + 1},
+		// two loops behind each other on bridges, found innermost first
+		{"nested bridges", []string{
+			"#########",
+			"#S     E#",
+			"#### ####",
+			"#       #",
+			"# ##### #",
+			"#       #",
+			"#### ####",
+			"#  # #  #",
+			"#       #",
+			"#########",
+		}, 1 + 16 + 1 + 12},
+		// the part that is not connected to the start is filled as well
+		{"unreachable part", []string{
+			"#########",
+			"#S     E#",
+			"#########",
+			"#     # #",
+			"# ### # #",
+			"#     # #",
+			"#########",
+		}, 12 + 3},
+		// a loop holding the end is never filled, only the dead end is
+		{"end behind a bridge", []string{
+			"#########",
+			"#S      #",
+			"#### ####",
+			"#   E   #",
+			"# ##### #",
+			"#       #",
+			"#########",
+		}, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, start, end := drawnMaze(test.maze...)
+			result, err := CulDeSacFilling{}.Solve(context.Background(), m, start, end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Visited) != test.filled {
+				t.Errorf("expected %d filled positions, got %d", test.filled, len(result.Visited))
+			}
+			if v := CheckPath(m, start, end, result.Path); !v.Optimal {
+				t.Errorf("expected a shortest route, got %s", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var ErrNotWallFollowable = errors.New("solver: wall follower is walking in circles, maze is not solvable by wall following")
+
+func init() {
+	Register("left-hand", func() Solver { return WallFollower{Hand: LEFT} })
+	Register("right-hand", func() Solver { return WallFollower{Hand: RIGHT} })
+}
+
+// WallFollower keeps one hand on the wall and walks till it reaches the end.
+// It only looks at the positions around it so in a maze with loops it can
+// circle an island forever, that is detected by arriving at a position
+// with the same heading twice and reported with ErrNotWallFollowable. When
+// the end can not be reached from the start at all ErrNoSolution is returned.
+type WallFollower struct {
+	Hand Direction // LEFT or RIGHT
+}
+
+func (f WallFollower) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	type state struct {
+		p Position
+		d Direction
+	}
+	result := &Result{Visited: []Position{start}, Path: []Position{start}}
+	seen := map[Position]int{start: 0} // position to index in the path
+	explored := map[Position]bool{start: true}
+	walked := make(map[state]bool)
+	current, heading := start, DOWN
+	tracker := newTracker(ctx)
+
+	for current != end {
+		if err := tracker.step(len(walked) + len(result.Visited)); err != nil {
+			return result, err
+		}
+		if walked[state{current, heading}] {
+			// circling, when the end is not connected to the start no walk reaches it
+			_, reachable := distances(m, start)
+			if _, ok := reachable[end]; !ok {
+				return result, ErrNoSolution
+			}
+			return result, ErrNotWallFollowable
+		}
+		walked[state{current, heading}] = true
+
+		moved := false
+		// prefer the hand side, then straight on, the other side and back
+		for _, d := range f.order(heading) {
+			if next := current.step(d); passable(m, next) {
+				current, heading, moved = next, d, true
+				break
+			}
+		}
+		if !moved {
+			return result, ErrNoSolution
+		}
+		result.Stats.Expanded++
+		if !explored[current] {
+			explored[current] = true
+			result.Visited = append(result.Visited, current)
+		}
+
+		// walking back over a position cuts the detour from the path
+		if i, ok := seen[current]; ok {
+			for _, p := range result.Path[i+1:] {
+				delete(seen, p)
+			}
+			result.Path = result.Path[:i+1]
+			tracker.emit(BACKTRACK, current)
+			continue
+		}
+		seen[current] = len(result.Path)
+		result.Path = append(result.Path, current)
+		if len(result.Path) > result.Stats.MaxFrontier {
+			result.Stats.MaxFrontier = len(result.Path)
+		}
+		tracker.visit(m, current)
+	}
+
+	tracker.emit(FOUND, end)
+	return result, nil
+}
+
+// order returns the directions to try from the given heading
+func (f WallFollower) order(heading Direction) [4]Direction {
+	if f.Hand == RIGHT {
+		return [4]Direction{heading.clockwise(), heading, heading.counterClockwise(), heading.clockwise().clockwise()}
+	}
+	return [4]Direction{heading.counterClockwise(), heading, heading.clockwise(), heading.clockwise().clockwise()}
+}
+
+// clockwise returns the direction after turning right
+func (d Direction) clockwise() Direction {
+	if d == DOWN {
+		return LEFT
+	}
+	return d << 1
+}
+
+// counterClockwise returns the direction after turning left
+func (d Direction) counterClockwise() Direction {
+	if d == LEFT {
+		return DOWN
+	}
+	return d >> 1
+}
+
+// step returns the adjacent position in the given direction
+func (p Position) step(d Direction) Position {
+	switch d {
+	case LEFT:
+		return Position{p.x - 1, p.y}
+	case UP:
+		return Position{p.x, p.y - 1}
+	case RIGHT:
+		return Position{p.x + 1, p.y}
+	case DOWN:
+		return Position{p.x, p.y + 1}
+	default:
+		return p
+	}
+}
+
+// passable checks if the position is a path inside the maze bounds
+func passable(m *builder.MazeImageMatrix, p Position) bool {
+	b := m.Bounds()
+	return p.x >= b.Min.X && p.x <= b.Max.X && p.y >= b.Min.Y && p.y <= b.Max.Y && m.Has(p.x, p.y, builder.PATH)
+}
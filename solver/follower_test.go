@@ -0,0 +1,73 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pbergman/maze/builder"
+)
+
+// testMaze returns a generated maze of n by n cells with its entrance and exit
+func testMaze(t *testing.T, generator string, n int, seed int64) (*builder.MazeImageMatrix, Position, Position) {
+	t.Helper()
+	m, err := builder.NewMazeImageBuilder(n, n).Generate(generator, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWalker(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, w.GetStart(), w.GetEnd()
+}
+
+func TestWallFollower(t *testing.T) {
+	tests := []struct {
+		generator string
+		hand      Direction
+	}{
+		{"backtracker", LEFT},
+		{"backtracker", RIGHT},
+		{"prim", LEFT},
+		{"prim", RIGHT},
+	}
+	for _, test := range tests {
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/%s/%d", test.generator, test.hand, seed), func(t *testing.T) {
+				m, start, end := testMaze(t, test.generator, 10, seed)
+				result, err := WallFollower{Hand: test.hand}.Solve(context.Background(), m, start, end)
+				if err != nil {
+					t.Fatalf("a perfect maze is always wall followable: %s", err)
+				}
+				if v := CheckPath(m, start, end, result.Path); !v.Valid {
+					t.Fatalf("invalid path: %s", v)
+				}
+			})
+		}
+	}
+}
+
+func TestWallFollowerUnreachableEnd(t *testing.T) {
+	for _, hand := range []Direction{LEFT, RIGHT} {
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", hand, seed), func(t *testing.T) {
+				m, start, _ := testMaze(t, "braid", 10, seed)
+				// wall in a cell in the middle and use it as end
+				grid := builder.NewGrid(m)
+				cell := grid.Cell(5, 5)
+				for _, s := range builder.Sides {
+					cell.Unlink(s)
+				}
+				p := grid.Pixel(cell)
+				m, end := grid.Matrix(), NewPosition(p.X, p.Y)
+
+				_, err := WallFollower{Hand: hand}.Solve(context.Background(), m, start, end)
+				if !errors.Is(err, ErrNoSolution) {
+					t.Fatalf("expected %v, got %v", ErrNoSolution, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var ErrNoSolution = errors.New("solver: no route from start to end")
+
+// Goals returns all start (entrances) and end (exits) positions marked on
+// the matrix, ordered top to bottom and left to right. The index in these
+// lists is used to select an entrance or exit, see Label.
+func Goals(m *builder.MazeImageMatrix) (starts []Position, ends []Position) {
+	for y := range m.M {
+		for x := range m.M[y] {
+			if m.Has(x, y, builder.START) {
+				starts = append(starts, Position{x, y})
+			}
+			if m.Has(x, y, builder.END) {
+				ends = append(ends, Position{x, y})
+			}
+		}
+	}
+	return starts, ends
+}
+
+// Label returns the letter name for an entrance or exit index (0 is A)
+func Label(i int) string {
+	if i < 0 {
+		return "?"
+	}
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return Label(i/26-1) + Label(i%26)
+}
+
+// ParseLabel returns the entrance or exit index for a letter name, it is
+// the reverse of Label
+func ParseLabel(label string) (int, error) {
+	i := 0
+	for _, c := range strings.ToUpper(label) {
+		if c < 'A' || c > 'Z' {
+			return -1, fmt.Errorf("solver: invalid label %q, expected letters", label)
+		}
+		i = i*26 + int(c-'A') + 1
+	}
+	if i == 0 {
+		return -1, fmt.Errorf("solver: empty label")
+	}
+	return i - 1, nil
+}
+
+// AddStart marks an extra entrance on the matrix
+func AddStart(m *builder.MazeImageMatrix, p Position) error {
+	if err := Validate(m, p); err != nil {
+		return fmt.Errorf("solver: start %w", err)
+	}
+	m.M[p.y][p.x] |= builder.START
+	return nil
+}
+
+// AddEnd marks an extra exit on the matrix
+func AddEnd(m *builder.MazeImageMatrix, p Position) error {
+	if err := Validate(m, p); err != nil {
+		return fmt.Errorf("solver: end %w", err)
+	}
+	m.M[p.y][p.x] |= builder.END
+	return nil
+}
+
+// NearestExit returns the shortest route from any of the given starts to
+// the closest end token, when no starts are given all entrances are used.
+// The route begins at the entrance used and ends at the exit reached.
+func NearestExit(m *builder.MazeImageMatrix, starts ...Position) ([]Position, error) {
+	if len(starts) == 0 {
+		starts, _ = Goals(m)
+	}
+	return route(m, starts, func(p Position) bool {
+		return m.Has(p.x, p.y, builder.END)
+	})
+}
+
+// Route returns the shortest route between the given start and end
+func Route(m *builder.MazeImageMatrix, from, to Position) ([]Position, error) {
+	return route(m, []Position{from}, func(p Position) bool {
+		return p == to
+	})
+}
+
+// route does a breadth first search from all sources at once and returns
+// the path to the first position accepted by done.
+func route(m *builder.MazeImageMatrix, sources []Position, done func(Position) bool) ([]Position, error) {
+	parents := make(map[Position]Position, len(sources))
+	queue := make([]Position, 0, len(sources))
+
+	for _, s := range sources {
+		if _, ok := parents[s]; !ok {
+			parents[s] = s
+			queue = append(queue, s)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		if current := queue[i]; done(current) {
+			path := []Position{current}
+			for parents[current] != current {
+				current = parents[current]
+				path = append(path, current)
+			}
+			for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+				path[a], path[b] = path[b], path[a]
+			}
+			return path, nil
+		}
+		for _, n := range Neighbours(m, queue[i]) {
+			if _, ok := parents[n]; !ok {
+				parents[n] = queue[i]
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return nil, ErrNoSolution
+}
+
+// unmark removes the given tokens from every position of the matrix
+func unmark(m *builder.MazeImageMatrix, t builder.MatrixToken) {
+	for y := range m.M {
+		for x := range m.M[y] {
+			m.M[y][x] &^= t
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package solver
+
+import "testing"
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		index int
+		label string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, test := range tests {
+		t.Run(test.label, func(t *testing.T) {
+			if label := Label(test.index); label != test.label {
+				t.Errorf("expected label %q for %d, got %q", test.label, test.index, label)
+			}
+			i, err := ParseLabel(test.label)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i != test.index {
+				t.Errorf("expected index %d for %q, got %d", test.index, test.label, i)
+			}
+		})
+	}
+}
+
+func TestParseLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		index int
+		valid bool
+	}{
+		{"a", 0, true},
+		{"ab", 27, true},
+		{"", -1, false},
+		{"A1", -1, false},
+		{"nearest!", -1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.label, func(t *testing.T) {
+			i, err := ParseLabel(test.label)
+			if (err == nil) != test.valid || i != test.index {
+				t.Errorf("expected %d (valid %t), got %d (%v)", test.index, test.valid, i, err)
+			}
+		})
+	}
+}
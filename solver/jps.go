@@ -0,0 +1,163 @@
+package solver
+
+import (
+	"container/heap"
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("jps", func() Solver { return JumpPoint{} })
+}
+
+// JumpPoint is A* that jumps over positions instead of queueing every one
+// of them. Of all equally long routes only the ones moving horizontally
+// before vertically are searched, so horizontal jumps stop at a forced
+// neighbour (an opening up or down that the previous position did not
+// have) and vertical jumps stop where a horizontal jump finds something.
+// In wide open areas only a few jump points are queued. The cost layer
+// is not used, every step counts as one.
+type JumpPoint struct{}
+
+func (JumpPoint) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	j := &jumper{m: m, end: end}
+	width := len(m.M[0])
+	parents := make([]int, width*len(m.M))
+	costs := make([]int, width*len(m.M))
+	closed := make([]bool, width*len(m.M))
+	heading := make([]Direction, width*len(m.M)) // direction the position was jumped to
+	open := &openSet{tie: CLOSEST}
+	result := &Result{}
+	tracker := newTracker(ctx)
+
+	parents[start.y*width+start.x] = start.y*width + start.x + 1
+	heap.Push(open, &node{p: start, h: Heuristics["manhattan"](start, end)})
+
+	for open.Len() > 0 {
+		if open.Len() > result.Stats.MaxFrontier {
+			result.Stats.MaxFrontier = open.Len()
+		}
+		current := heap.Pop(open).(*node)
+		index := current.p.y*width + current.p.x
+		if closed[index] {
+			continue
+		}
+		closed[index] = true
+		result.Visited = append(result.Visited, current.p)
+		result.Stats.Expanded++
+		if err := tracker.step(len(result.Visited) + open.Len()); err != nil {
+			return result, err
+		}
+		tracker.visit(m, current.p)
+
+		if current.p == end {
+			tracker.emit(FOUND, end)
+			result.Path = j.expand(path(parents, width, start, end))
+			result.Open = open.positions(closed, width)
+			return result, nil
+		}
+
+		for _, d := range j.directions(current.p, heading[index]) {
+			n, ok := j.jump(current.p, d)
+			if !ok {
+				continue
+			}
+			next, g := n.y*width+n.x, current.g+distance(current.p, n)
+			if closed[next] || (parents[next] != 0 && costs[next] <= g) {
+				continue
+			}
+			parents[next], costs[next], heading[next] = index+1, g, d
+			heap.Push(open, &node{p: n, g: g, h: Heuristics["manhattan"](n, end)})
+		}
+	}
+
+	return result, ErrNoSolution
+}
+
+type jumper struct {
+	m   *builder.MazeImageMatrix
+	end Position
+}
+
+// directions returns the directions to jump to from a position that was
+// reached by jumping in the given direction, 0 for the start.
+func (j *jumper) directions(p Position, heading Direction) []Direction {
+	switch heading {
+	case LEFT, RIGHT:
+		list := []Direction{heading}
+		back := p.step(heading.clockwise().clockwise())
+		for _, d := range [2]Direction{UP, DOWN} {
+			if j.forced(back, p, d) {
+				list = append(list, d)
+			}
+		}
+		return list
+	case UP, DOWN:
+		return []Direction{heading, LEFT, RIGHT}
+	default:
+		return []Direction{LEFT, UP, RIGHT, DOWN}
+	}
+}
+
+// forced checks if the position has an opening to the given side that the
+// previous position did not have
+func (j *jumper) forced(previous, p Position, d Direction) bool {
+	return passable(j.m, p.step(d)) && !passable(j.m, previous.step(d))
+}
+
+// jump walks in the given direction till it finds a jump point
+func (j *jumper) jump(p Position, d Direction) (Position, bool) {
+	for {
+		previous := p
+		p = p.step(d)
+		if !passable(j.m, p) {
+			return p, false
+		}
+		if p == j.end {
+			return p, true
+		}
+		switch d {
+		case LEFT, RIGHT:
+			if j.forced(previous, p, UP) || j.forced(previous, p, DOWN) {
+				return p, true
+			}
+		case UP, DOWN:
+			if _, ok := j.jump(p, LEFT); ok {
+				return p, true
+			}
+			if _, ok := j.jump(p, RIGHT); ok {
+				return p, true
+			}
+		}
+	}
+}
+
+// expand fills in the positions between the jump points
+func (j *jumper) expand(points []Position) []Position {
+	if len(points) == 0 {
+		return points
+	}
+	list := []Position{points[0]}
+	for _, p := range points[1:] {
+		for current := list[len(list)-1]; current != p; {
+			switch {
+			case current.x < p.x:
+				current.x++
+			case current.x > p.x:
+				current.x--
+			case current.y < p.y:
+				current.y++
+			default:
+				current.y--
+			}
+			list = append(list, current)
+		}
+	}
+	return list
+}
+
+// distance returns the steps between two positions on a line
+func distance(a, b Position) int {
+	return int(Heuristics["manhattan"](a, b))
+}
@@ -0,0 +1,189 @@
+package solver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var ErrInvalidMove = errors.New("solver: invalid move")
+
+// maxMoves limits the moves ParseMoves returns so a short run-length
+// encoded string can not use up the memory
+const maxMoves = 1 << 20
+
+// Leg is a number of moves in the same direction
+type Leg struct {
+	Direction Direction
+	Count     int
+}
+
+// Moves returns the direction of every step on the path
+func Moves(path []Position) []Direction {
+	moves := make([]Direction, 0, len(path))
+	for i := 1; i < len(path); i++ {
+		moves = append(moves, direction(path[i-1], path[i]))
+	}
+	return moves
+}
+
+// CellMoves returns the moves from cell to cell of the maze grid instead
+// of from pixel to pixel, positions that are not a cell (the passages
+// between cells and the openings in the outer wall) are skipped.
+func CellMoves(m *builder.MazeImageMatrix, path []Position) []Direction {
+	grid := builder.NewGrid(m)
+	moves := make([]Direction, 0, len(path)/2)
+	var previous *builder.Cell
+	for _, p := range path {
+		cell := grid.At(p.x, p.y)
+		if cell == nil || cell == previous {
+			continue
+		}
+		if previous != nil {
+			moves = append(moves, direction(Position{previous.X, previous.Y}, Position{cell.X, cell.Y}))
+		}
+		previous = cell
+	}
+	return moves
+}
+
+// direction returns the direction from a to b, the positions are on a line
+func direction(a, b Position) Direction {
+	switch {
+	case b.x < a.x:
+		return LEFT
+	case b.x > a.x:
+		return RIGHT
+	case b.y < a.y:
+		return UP
+	default:
+		return DOWN
+	}
+}
+
+// Legs groups the moves in the same direction
+func Legs(moves []Direction) []Leg {
+	legs := make([]Leg, 0)
+	for _, d := range moves {
+		if len(legs) > 0 && legs[len(legs)-1].Direction == d {
+			legs[len(legs)-1].Count++
+		} else {
+			legs = append(legs, Leg{d, 1})
+		}
+	}
+	return legs
+}
+
+// FormatMoves returns the moves with a letter for every move, as "RRDDLU"
+func FormatMoves(moves []Direction) string {
+	buff := new(bytes.Buffer)
+	for _, d := range moves {
+		buff.WriteByte(d.String()[0])
+	}
+	return buff.String()
+}
+
+// FormatRuns returns the moves run-length encoded, as "R2 D2 L1 U1"
+func FormatRuns(moves []Direction) string {
+	list := make([]string, 0)
+	for _, r := range Legs(moves) {
+		list = append(list, fmt.Sprintf("%c%d", r.Direction.String()[0], r.Count))
+	}
+	return strings.Join(list, " ")
+}
+
+// Instructions returns turn by turn directions for the moves, as "go east
+// 3 cells, turn right and go south 2 cells". Headings are given as compass
+// points so left and right are only used for turns, the legs are counted
+// in the given unit, as "cell" or "step".
+func Instructions(moves []Direction, unit string) []string {
+	list := make([]string, 0)
+	legs := Legs(moves)
+	for i, r := range legs {
+		count := fmt.Sprintf("%d %s", r.Count, unit)
+		if r.Count != 1 {
+			count += "s"
+		}
+		heading := r.Direction.compass()
+		switch {
+		case i == 0:
+			list = append(list, fmt.Sprintf("go %s %s", heading, count))
+		case r.Direction == legs[i-1].Direction.clockwise():
+			list = append(list, fmt.Sprintf("turn right and go %s %s", heading, count))
+		case r.Direction == legs[i-1].Direction.counterClockwise():
+			list = append(list, fmt.Sprintf("turn left and go %s %s", heading, count))
+		default:
+			list = append(list, fmt.Sprintf("turn around and go %s %s", heading, count))
+		}
+	}
+	return append(list, "you reached the end")
+}
+
+// compass returns the compass point of the direction, up is north
+func (d Direction) compass() string {
+	switch d {
+	case LEFT:
+		return "west"
+	case UP:
+		return "north"
+	case RIGHT:
+		return "east"
+	case DOWN:
+		return "south"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMoves reads a move string with a letter for every move, U(p),
+// D(own), L(eft) or R(ight), a letter followed by a number is repeated
+// that many times so run-length encoded moves ("R2 D2") are read as well.
+// Case and white space are ignored.
+func ParseMoves(s string) ([]Direction, error) {
+	moves := make([]Direction, 0, len(s))
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		var d Direction
+		switch unicode.ToUpper(runes[i]) {
+		case 'U':
+			d = UP
+		case 'D':
+			d = DOWN
+		case 'L':
+			d = LEFT
+		case 'R':
+			d = RIGHT
+		default:
+			if unicode.IsSpace(runes[i]) {
+				continue
+			}
+			return nil, fmt.Errorf("%w %q at %d", ErrInvalidMove, runes[i], i)
+		}
+		end := i + 1
+		for end < len(runes) && unicode.IsDigit(runes[end]) {
+			end++
+		}
+		count := 1
+		if end > i+1 {
+			n, err := strconv.Atoi(string(runes[i+1 : end]))
+			if err != nil {
+				return nil, fmt.Errorf("%w %q at %d: %s", ErrInvalidMove, string(runes[i:end]), i, err)
+			}
+			count = n
+		}
+		// compare to what is left, len(moves)+count overflows for huge counts
+		if count > maxMoves-len(moves) {
+			return nil, fmt.Errorf("%w: more than %d moves", ErrInvalidMove, maxMoves)
+		}
+		for ; count > 0; count-- {
+			moves = append(moves, d)
+		}
+		i = end - 1
+	}
+	return moves, nil
+}
@@ -0,0 +1,103 @@
+package solver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		moves []Direction
+		err   error
+	}{
+		{"empty", "", []Direction{}, nil},
+		{"letters", "UDLR", []Direction{UP, DOWN, LEFT, RIGHT}, nil},
+		{"case and spaces", " u d\tl\nr ", []Direction{UP, DOWN, LEFT, RIGHT}, nil},
+		{"runs", "R2 D3", []Direction{RIGHT, RIGHT, DOWN, DOWN, DOWN}, nil},
+		{"zero run", "R0U", []Direction{UP}, nil},
+		{"unknown letter", "RX", nil, ErrInvalidMove},
+		{"leading number", "2R", nil, ErrInvalidMove},
+		{"limit", "R1048576", make([]Direction, maxMoves), nil},
+		{"over limit", "R1048577", nil, ErrInvalidMove},
+		{"over limit in total", "U R1048576", nil, ErrInvalidMove},
+		{"max int", "R R9223372036854775807", nil, ErrInvalidMove},
+		{"overflowing int", "R99999999999999999999", nil, ErrInvalidMove},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moves, err := ParseMoves(test.in)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if test.name == "limit" {
+				if len(moves) != maxMoves || moves[0] != RIGHT || moves[maxMoves-1] != RIGHT {
+					t.Fatalf("expected %d moves right, got %d", maxMoves, len(moves))
+				}
+				return
+			}
+			if !reflect.DeepEqual(moves, test.moves) {
+				t.Fatalf("expected %v, got %v", test.moves, moves)
+			}
+		})
+	}
+}
+
+func TestFormatMoves(t *testing.T) {
+	tests := []struct {
+		moves []Direction
+		plain string
+		runs  string
+	}{
+		{[]Direction{}, "", ""},
+		{[]Direction{RIGHT}, "R", "R1"},
+		{[]Direction{RIGHT, RIGHT, DOWN, LEFT, LEFT, LEFT}, "RRDLLL", "R2 D1 L3"},
+		{[]Direction{UP, DOWN, UP}, "UDU", "U1 D1 U1"},
+	}
+	for _, test := range tests {
+		t.Run(test.plain, func(t *testing.T) {
+			if s := FormatMoves(test.moves); s != test.plain {
+				t.Errorf("expected moves %q, got %q", test.plain, s)
+			}
+			if s := FormatRuns(test.moves); s != test.runs {
+				t.Errorf("expected runs %q, got %q", test.runs, s)
+			}
+			for _, s := range []string{test.plain, test.runs} {
+				moves, err := ParseMoves(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(moves, test.moves) {
+					t.Errorf("expected %q to parse back to %v, got %v", s, test.moves, moves)
+				}
+			}
+		})
+	}
+}
+
+func TestInstructions(t *testing.T) {
+	tests := []struct {
+		moves string
+		want  []string
+	}{
+		{"", []string{"you reached the end"}},
+		{"R", []string{"go east 1 cell", "you reached the end"}},
+		{"R3 D2", []string{"go east 3 cells", "turn right and go south 2 cells", "you reached the end"}},
+		{"D U", []string{"go south 1 cell", "turn around and go north 1 cell", "you reached the end"}},
+		{"L2 D", []string{"go west 2 cells", "turn left and go south 1 cell", "you reached the end"}},
+	}
+	for _, test := range tests {
+		t.Run(test.moves, func(t *testing.T) {
+			moves, err := ParseMoves(test.moves)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Instructions(moves, "cell"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %s, got %s", strings.Join(test.want, ", "), strings.Join(got, ", "))
+			}
+		})
+	}
+}
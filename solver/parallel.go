@@ -0,0 +1,197 @@
+package solver
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("parallel", func() Solver { return NewParallel() })
+}
+
+// Parallel walks the maze depth first on multiple goroutines. A worker
+// hands the branches it finds at a junction to idle workers and keeps
+// walking one of them itself, positions are claimed in a shared bitmap so
+// every position is walked only once. All workers stop as soon as one of
+// them reaches the end, the route found is not always the shortest.
+type Parallel struct {
+	Workers int
+}
+
+func NewParallel() *Parallel {
+	return &Parallel{Workers: runtime.NumCPU()}
+}
+
+func (s *Parallel) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	width := len(m.M[0])
+	p := &parallel{
+		m:       m,
+		end:     end,
+		width:   width,
+		bitmap:  make([]uint32, (width*len(m.M)+31)/32),
+		parents: make([]int, width*len(m.M)),
+		tasks:   make(chan Position, workers*64),
+		stop:    make(chan struct{}),
+		pending: 1,
+		tracker: newTracker(ctx),
+	}
+	p.claim(start)
+	p.parents[start.y*width+start.x] = start.y*width + start.x + 1
+	p.tasks <- start
+
+	var wg sync.WaitGroup
+	visited := make([][]Position, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			visited[i] = p.work()
+		}(i)
+	}
+	wg.Wait()
+
+	// merge the positions per worker so the animation shows them side by side
+	result := &Result{}
+	for i, more := 0, true; more; i++ {
+		more = false
+		for _, list := range visited {
+			if i < len(list) {
+				result.Visited = append(result.Visited, list[i])
+				more = true
+			}
+		}
+	}
+
+	result.Stats.Expanded = len(result.Visited)
+
+	if p.err != nil {
+		return result, p.err
+	}
+	if atomic.LoadInt32(&p.found) == 0 {
+		return result, ErrNoSolution
+	}
+	result.Path = path(p.parents, width, start, end)
+	return result, nil
+}
+
+type parallel struct {
+	m       *builder.MazeImageMatrix
+	end     Position
+	width   int
+	bitmap  []uint32      // claimed positions
+	parents []int         // parent index + 1, written by the worker claiming the position
+	tasks   chan Position // branches waiting for a worker
+	stop    chan struct{} // closed when the end is found or solving is aborted
+	once    sync.Once
+	pending int64 // queued and running tasks, the tasks are closed when it drops to 0
+	found   int32
+	tracker *tracker
+	err     error // reason solving was aborted
+}
+
+// halt stops all workers, the first call decides why
+func (p *parallel) halt(err error) {
+	p.once.Do(func() {
+		if err == nil {
+			atomic.StoreInt32(&p.found, 1)
+		}
+		p.err = err
+		close(p.stop)
+	})
+}
+
+// claim marks the position as visited, it returns false when another
+// worker was first.
+func (p *parallel) claim(position Position) bool {
+	index := position.y*p.width + position.x
+	word, mask := &p.bitmap[index/32], uint32(1)<<uint(index%32)
+	for {
+		old := atomic.LoadUint32(word)
+		if old&mask != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapUint32(word, old, old|mask) {
+			return true
+		}
+	}
+}
+
+// work takes tasks till the end is found or all branches are walked and
+// returns the positions it visited.
+func (p *parallel) work() []Position {
+	visited := make([]Position, 0)
+	for {
+		select {
+		case <-p.stop:
+			return visited
+		case task, ok := <-p.tasks:
+			if !ok {
+				return visited
+			}
+			visited = p.walk(task, visited)
+			if atomic.AddInt64(&p.pending, -1) == 0 {
+				close(p.tasks)
+			}
+		}
+	}
+}
+
+// walk goes depth first from the given position, branches are offered to
+// the other workers when there is room in the queue.
+func (p *parallel) walk(from Position, visited []Position) []Position {
+	stack := []Position{from}
+	for len(stack) > 0 {
+		select {
+		case <-p.stop:
+			return visited
+		default:
+		}
+
+		// every step keeps one visited position
+		if err := p.tracker.step(int(atomic.LoadInt64(&p.tracker.steps)) + 1); err != nil {
+			p.halt(err)
+			return visited
+		}
+
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		visited = append(visited, current)
+		p.tracker.visit(p.m, current)
+
+		if current == p.end {
+			p.tracker.emit(FOUND, current)
+			p.halt(nil)
+			return visited
+		}
+
+		for _, n := range Neighbours(p.m, current) {
+			if p.claim(n) {
+				p.parents[n.y*p.width+n.x] = current.y*p.width + current.x + 1
+				stack = append(stack, n)
+			}
+		}
+
+		// keep the last branch, the oldest ones are given away first
+		for len(stack) > 1 {
+			atomic.AddInt64(&p.pending, 1)
+			select {
+			case p.tasks <- stack[0]:
+				stack = stack[1:]
+				continue
+			default:
+				atomic.AddInt64(&p.pending, -1)
+			}
+			break
+		}
+	}
+	return visited
+}
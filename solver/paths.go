@@ -0,0 +1,324 @@
+package solver
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+
+	"github.com/pbergman/maze/builder"
+)
+
+// Paths returns the simple routes (routes not passing a position twice)
+// from start to end, limit stops the search after that many routes and 0
+// means no limit. The number of routes grows fast with every loop in the
+// maze so use a limit, or a budget on the context, for braided mazes.
+func Paths(ctx context.Context, m *builder.MazeImageMatrix, start, end Position, limit int) ([][]Position, error) {
+	g, err := newJunctions(m, start, end)
+	if err != nil {
+		return nil, err
+	}
+	list := make([][]Position, 0)
+	err = g.walk(newTracker(ctx), func(route []*corridor) bool {
+		list = append(list, g.positions(route))
+		return limit <= 0 || len(list) < limit
+	})
+	if err == nil && len(list) == 0 {
+		err = ErrNoSolution
+	}
+	return list, err
+}
+
+// CountPaths returns the number of simple routes from start to end without
+// keeping them, counting stops at the limit and 0 means no limit. To check
+// a maze has exactly two solutions count with a limit of 3.
+func CountPaths(ctx context.Context, m *builder.MazeImageMatrix, start, end Position, limit int) (int, error) {
+	g, err := newJunctions(m, start, end)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = g.walk(newTracker(ctx), func(route []*corridor) bool {
+		count++
+		return limit <= 0 || count < limit
+	})
+	return count, err
+}
+
+// KShortest returns at most k simple routes from start to end ordered by
+// cost (see MazeImageMatrix.Cost) with Yen's algorithm, routes with the
+// same cost are ordered by length.
+func KShortest(ctx context.Context, m *builder.MazeImageMatrix, start, end Position, k int) ([][]Position, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("solver: k should be at least 1, got %d", k)
+	}
+	g, err := newJunctions(m, start, end)
+	if err != nil {
+		return nil, err
+	}
+	tracker := newTracker(ctx)
+	first, ok := g.shortest(g.start, nil, nil)
+	if !ok {
+		return nil, ErrNoSolution
+	}
+	found := []*trail{first}
+	candidates := make([]*trail, 0)
+
+	for len(found) < k {
+		last := found[len(found)-1]
+		// deviate from the last route at every junction on it
+		for i := range last.corridors {
+			if err := tracker.step(len(candidates) + len(found)); err != nil {
+				return g.routes(found), err
+			}
+			root := &trail{corridors: last.corridors[:i]}
+			removed := make(map[int]bool)
+			for _, r := range found {
+				if len(r.corridors) > i && r.startsWith(root) {
+					removed[r.corridors[i].id] = true
+				}
+			}
+			blocked := make(map[int]bool)
+			for _, c := range root.corridors {
+				blocked[c.from] = true
+			}
+			spur, ok := g.shortest(last.node(g, i), blocked, removed)
+			if !ok {
+				continue
+			}
+			candidate := &trail{corridors: append(append([]*corridor{}, root.corridors...), spur.corridors...)}
+			for _, c := range candidate.corridors {
+				candidate.cost += c.cost
+				candidate.length += len(c.cells)
+			}
+			if !candidate.in(found) && !candidate.in(candidates) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i, c := range candidates {
+			if c.cost < candidates[best].cost || (c.cost == candidates[best].cost && c.length < candidates[best].length) {
+				best = i
+			}
+		}
+		found = append(found, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	return g.routes(found), nil
+}
+
+// corridor is a passage between two junctions, walked in one direction
+type corridor struct {
+	id    int        // same for both directions
+	from  int        // junction index
+	to    int        // junction index
+	cells []Position // positions after from up to and including to
+	cost  int
+}
+
+// junctions is the maze reduced to a graph of the positions that do not
+// have exactly two ways (and start and end) connected by corridors, as
+// routes can only split at a junction.
+type junctions struct {
+	nodes      []Position
+	index      map[Position]int
+	corridors  [][]*corridor // outgoing corridors per junction
+	start, end int
+}
+
+func newJunctions(m *builder.MazeImageMatrix, start, end Position) (*junctions, error) {
+	if err := Validate(m, start); err != nil {
+		return nil, fmt.Errorf("solver: start %w", err)
+	}
+	if err := Validate(m, end); err != nil {
+		return nil, fmt.Errorf("solver: end %w", err)
+	}
+	g := &junctions{index: make(map[Position]int)}
+	isJunction := func(p Position) bool {
+		return p == start || p == end || len(Neighbours(m, p)) != 2
+	}
+	g.start = g.add(start)
+	g.end = g.add(end)
+
+	ids := make(map[[2]Position]int) // junction and first position of a corridor => id
+	for i := 0; i < len(g.nodes); i++ {
+		from := g.nodes[i]
+		for _, first := range Neighbours(m, from) {
+			c := &corridor{id: len(ids), from: i}
+			previous, current := from, first
+			for {
+				c.cells = append(c.cells, current)
+				c.cost += m.Cost(current.x, current.y)
+				if isJunction(current) {
+					break
+				}
+				for _, n := range Neighbours(m, current) {
+					if n != previous {
+						previous, current = current, n
+						break
+					}
+				}
+			}
+			// a corridor returning to its junction is never part of a simple route
+			if current == from {
+				continue
+			}
+			if id, ok := ids[[2]Position{current, previous}]; ok {
+				c.id = id
+			}
+			ids[[2]Position{from, first}] = c.id
+			if _, ok := g.index[current]; !ok {
+				g.add(current)
+			}
+			c.to = g.index[current]
+			g.corridors[i] = append(g.corridors[i], c)
+		}
+	}
+	return g, nil
+}
+
+// add registers a junction and returns its index
+func (g *junctions) add(p Position) int {
+	if i, ok := g.index[p]; ok {
+		return i
+	}
+	g.index[p] = len(g.nodes)
+	g.nodes = append(g.nodes, p)
+	g.corridors = append(g.corridors, nil)
+	return g.index[p]
+}
+
+// walk goes depth first over all simple routes from start to end and calls
+// found for every one of them till it returns false.
+func (g *junctions) walk(t *tracker, found func(route []*corridor) bool) error {
+	if g.start == g.end {
+		found(nil)
+		return nil
+	}
+	on := make([]bool, len(g.nodes))
+	on[g.start] = true
+	route := make([]*corridor, 0)
+	var next func(at int) (bool, error)
+	next = func(at int) (bool, error) {
+		for _, c := range g.corridors[at] {
+			if on[c.to] {
+				continue
+			}
+			if err := t.step(len(route)); err != nil {
+				return false, err
+			}
+			route = append(route, c)
+			if c.to == g.end {
+				if !found(route) {
+					return false, nil
+				}
+			} else {
+				on[c.to] = true
+				more, err := next(c.to)
+				on[c.to] = false
+				if !more || err != nil {
+					return false, err
+				}
+			}
+			route = route[:len(route)-1]
+		}
+		return true, nil
+	}
+	_, err := next(g.start)
+	return err
+}
+
+// shortest returns the cheapest route from the junction to the end that
+// does not use the blocked junctions or removed corridors.
+func (g *junctions) shortest(from int, blocked, removed map[int]bool) (*trail, bool) {
+	costs := make([]int, len(g.nodes))
+	parents := make([]*corridor, len(g.nodes))
+	closed := make([]bool, len(g.nodes))
+	open := &openSet{tie: OLDEST}
+	heap.Push(open, &node{p: g.nodes[from]})
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		at := g.index[current.p]
+		if closed[at] {
+			continue
+		}
+		closed[at] = true
+		if at == g.end {
+			r := &trail{cost: current.g}
+			for at != from {
+				r.corridors = append([]*corridor{parents[at]}, r.corridors...)
+				r.length += len(parents[at].cells)
+				at = parents[at].from
+			}
+			return r, true
+		}
+		for _, c := range g.corridors[at] {
+			if closed[c.to] || blocked[c.to] || removed[c.id] {
+				continue
+			}
+			if cost := current.g + c.cost; parents[c.to] == nil || cost < costs[c.to] {
+				costs[c.to], parents[c.to] = cost, c
+				heap.Push(open, &node{p: g.nodes[c.to], g: cost})
+			}
+		}
+	}
+	return nil, false
+}
+
+// positions returns the route as positions starting with start
+func (g *junctions) positions(route []*corridor) []Position {
+	list := []Position{g.nodes[g.start]}
+	for _, c := range route {
+		list = append(list, c.cells...)
+	}
+	return list
+}
+
+func (g *junctions) routes(list []*trail) [][]Position {
+	routes := make([][]Position, len(list))
+	for i, r := range list {
+		routes[i] = g.positions(r.corridors)
+	}
+	return routes
+}
+
+// trail is a list of corridors from start to end
+type trail struct {
+	corridors []*corridor
+	cost      int
+	length    int
+}
+
+// node returns the junction the i-th corridor starts from
+func (r *trail) node(g *junctions, i int) int {
+	if i == 0 {
+		return g.start
+	}
+	return r.corridors[i-1].to
+}
+
+// startsWith checks if the trail begins with the corridors of the other
+func (r *trail) startsWith(other *trail) bool {
+	if len(other.corridors) > len(r.corridors) {
+		return false
+	}
+	for i, c := range other.corridors {
+		if r.corridors[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// in checks if the same trail is in the list
+func (r *trail) in(list []*trail) bool {
+	for _, other := range list {
+		if len(other.corridors) == len(r.corridors) && other.startsWith(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// ladderMaze has two loops, the simple routes from S to E are 8, 8, 8 and
+// 16 steps long
+var ladderMaze = []string{
+	"#######",
+	"#S    #",
+	"# ### #",
+	"#     #",
+	"# ### #",
+	"#    E#",
+	"#######",
+}
+
+// routeCost returns the cost of walking the route, the start is free
+func routeCost(route []Position, cost func(x, y int) int) int {
+	total := 0
+	for _, p := range route[1:] {
+		total += cost(p.x, p.y)
+	}
+	return total
+}
+
+// checkSimple fails when the route is not a walk from start to end or
+// passes a position twice
+func checkSimple(t *testing.T, route []Position, verdict *Verdict) {
+	t.Helper()
+	if !verdict.Valid {
+		t.Fatalf("invalid route: %s", verdict)
+	}
+	seen := make(map[Position]bool)
+	for _, p := range route {
+		if seen[p] {
+			t.Fatalf("route passes %s twice", p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestKShortest(t *testing.T) {
+	tests := []struct {
+		k      int
+		costly bool // a higher cost in the middle of the ladder
+		costs  []int
+	}{
+		{1, false, []int{8}},
+		{2, false, []int{8, 8}},
+		{3, false, []int{8, 8, 8}},
+		{4, false, []int{8, 8, 8, 16}},
+		{10, false, []int{8, 8, 8, 16}},
+		{1, true, []int{8}},
+		{3, true, []int{8, 8, 16}},
+		{10, true, []int{8, 8, 16, 24}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d/%t", test.k, test.costly), func(t *testing.T) {
+			m, start, end := drawnMaze(ladderMaze...)
+			if test.costly {
+				m.SetCost(4, 4, 9)
+			}
+			routes, err := KShortest(context.Background(), m, start, end, test.k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			costs := make([]int, len(routes))
+			for i, route := range routes {
+				checkSimple(t, route, CheckPath(m, start, end, route))
+				costs[i] = routeCost(route, m.Cost)
+				for _, other := range routes[:i] {
+					if reflect.DeepEqual(route, other) {
+						t.Fatalf("route %d is returned twice", i)
+					}
+				}
+			}
+			if !reflect.DeepEqual(costs, test.costs) {
+				t.Errorf("expected routes costing %v, got %v", test.costs, costs)
+			}
+		})
+	}
+}
+
+func TestKShortestInvalidK(t *testing.T) {
+	m, start, end := drawnMaze(ladderMaze...)
+	for _, k := range []int{0, -1} {
+		if routes, err := KShortest(context.Background(), m, start, end, k); err == nil || routes != nil {
+			t.Errorf("expected an error and no routes for k %d, got %d routes and %v", k, len(routes), err)
+		}
+	}
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		limit int
+		count int
+	}{
+		{0, 4},
+		{2, 2},
+		{4, 4},
+		{5, 4},
+	}
+	m, start, end := drawnMaze(ladderMaze...)
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.limit), func(t *testing.T) {
+			routes, err := Paths(context.Background(), m, start, end, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(routes) != test.count {
+				t.Fatalf("expected %d routes, got %d", test.count, len(routes))
+			}
+			for _, route := range routes {
+				checkSimple(t, route, CheckPath(m, start, end, route))
+			}
+			count, err := CountPaths(context.Background(), m, start, end, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != test.count {
+				t.Errorf("expected to count %d routes, got %d", test.count, count)
+			}
+		})
+	}
+}
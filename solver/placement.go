@@ -0,0 +1,226 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pbergman/maze/builder"
+)
+
+type Placement uint8
+
+const (
+	SCAN     Placement = iota // first two path pixels found scanning the border clockwise
+	LONGEST                   // both ends of the longest path in the maze
+	BORDER                    // ends of the longest path between border pixels
+	OPPOSITE                  // random pixels on opposite sides of the maze
+)
+
+var (
+	ErrNoPlacement = errors.New("solver: could not find two positions for start and end")
+	ErrOutOfBounds = errors.New("position is outside the maze")
+	ErrWall        = errors.New("position is a wall")
+	ErrNoPath      = errors.New("position is not a path")
+)
+
+func (p Placement) String() string {
+	switch p {
+	case SCAN:
+		return "scan"
+	case LONGEST:
+		return "longest"
+	case BORDER:
+		return "border"
+	case OPPOSITE:
+		return "opposite"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePlacement returns the placement for the given name
+func ParsePlacement(name string) (Placement, error) {
+	for _, p := range []Placement{SCAN, LONGEST, BORDER, OPPOSITE} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return SCAN, fmt.Errorf("unknown placement %q", name)
+}
+
+// Place will mark start and end on the matrix based on the placement mode,
+// the random source is only used by OPPOSITE and may be nil.
+func Place(m *builder.MazeImageMatrix, p Placement, r *rand.Rand) error {
+	var start, end Position
+	var ok bool
+
+	switch p {
+	case SCAN:
+		start, end, ok = scan(m)
+	case LONGEST:
+		start, end, ok = longest(m, func(Position) bool { return true })
+	case BORDER:
+		frame := m.Frame()
+		start, end, ok = longest(m, func(p Position) bool {
+			return p.x <= frame.Min.X+1 || p.y <= frame.Min.Y+1 || p.x >= frame.Max.X-1 || p.y >= frame.Max.Y-1
+		})
+	case OPPOSITE:
+		if r == nil {
+			r = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		start, end, ok = opposite(m, r)
+	default:
+		return fmt.Errorf("unknown placement %d", p)
+	}
+
+	if !ok {
+		return ErrNoPlacement
+	}
+
+	return PlaceAt(m, start, end)
+}
+
+// PlaceAt will mark start and end on the given positions, removing
+// existing start and end tokens from the matrix.
+func PlaceAt(m *builder.MazeImageMatrix, start, end Position) error {
+	if err := Validate(m, start); err != nil {
+		return fmt.Errorf("solver: start %w", err)
+	}
+	if err := Validate(m, end); err != nil {
+		return fmt.Errorf("solver: end %w", err)
+	}
+	unmark(m, builder.START|builder.END)
+	m.M[start.y][start.x] |= builder.START
+	m.M[end.y][end.x] |= builder.END
+	return nil
+}
+
+// Validate checks if the given position can be used as start or end, the
+// returned error wraps ErrOutOfBounds, ErrWall or ErrNoPath.
+func Validate(m *builder.MazeImageMatrix, p Position) error {
+	bounds := m.Bounds()
+	switch {
+	case p.x < bounds.Min.X || p.x > bounds.Max.X || p.y < bounds.Min.Y || p.y > bounds.Max.Y:
+		return fmt.Errorf("%s: %w (%s - %s)", p, ErrOutOfBounds, NewPosition(bounds.Min.X, bounds.Min.Y), NewPosition(bounds.Max.X, bounds.Max.Y))
+	case m.Has(p.x, p.y, builder.WALL):
+		return fmt.Errorf("%s: %w", p, ErrWall)
+	case !m.Has(p.x, p.y, builder.PATH):
+		return fmt.Errorf("%s: %w", p, ErrNoPath)
+	}
+	return nil
+}
+
+// scan returns the first two path pixels walking the border clockwise
+func scan(m *builder.MazeImageMatrix) (Position, Position, bool) {
+	found := make([]Position, 0, 2)
+	w := &Walker{b: m.Bounds(), m: m}
+	pos := Position{w.b.Min.X, w.b.Min.Y}
+	for _, step := range []func(*Position) bool{w.right, w.down, w.left, w.up} {
+		for len(found) < 2 && step(&pos) {
+			if m.Has(pos.x, pos.y, builder.PATH) {
+				found = append(found, pos)
+			}
+		}
+	}
+	if len(found) < 2 {
+		return Position{}, Position{}, false
+	}
+	return found[0], found[1], true
+}
+
+// longest does a two pass breadth first search, the farthest candidate from
+// any candidate is one end of the longest path and the farthest candidate
+// from that is the other end.
+func longest(m *builder.MazeImageMatrix, candidate func(Position) bool) (Position, Position, bool) {
+	bounds := m.Bounds()
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if p := (Position{x, y}); m.Has(x, y, builder.PATH) && candidate(p) {
+				a, _ := farthest(m, p, candidate)
+				b, distance := farthest(m, a, candidate)
+				return a, b, distance > 0
+			}
+		}
+	}
+	return Position{}, Position{}, false
+}
+
+// farthest returns the reachable candidate with the longest distance to p
+func farthest(m *builder.MazeImageMatrix, p Position, candidate func(Position) bool) (Position, int) {
+	result, distance := p, 0
+	order, list := distances(m, p)
+	for _, pos := range order {
+		if list[pos] > distance && candidate(pos) {
+			result, distance = pos, list[pos]
+		}
+	}
+	return result, distance
+}
+
+// opposite picks a random side and returns random path pixels of the cells
+// along that side and the side facing it.
+func opposite(m *builder.MazeImageMatrix, r *rand.Rand) (Position, Position, bool) {
+	frame := m.Frame()
+	sides := [4][]Position{}
+	for y := frame.Min.Y + 1; y < frame.Max.Y; y++ {
+		for x := frame.Min.X + 1; x < frame.Max.X; x++ {
+			if !m.Has(x, y, builder.PATH) {
+				continue
+			}
+			if y == frame.Min.Y+1 {
+				sides[0] = append(sides[0], Position{x, y})
+			}
+			if y == frame.Max.Y-1 {
+				sides[2] = append(sides[2], Position{x, y})
+			}
+			if x == frame.Min.X+1 {
+				sides[3] = append(sides[3], Position{x, y})
+			}
+			if x == frame.Max.X-1 {
+				sides[1] = append(sides[1], Position{x, y})
+			}
+		}
+	}
+	side := r.Intn(2)
+	if len(sides[side]) == 0 || len(sides[side+2]) == 0 {
+		side ^= 1
+	}
+	if len(sides[side]) == 0 || len(sides[side+2]) == 0 {
+		return Position{}, Position{}, false
+	}
+	if r.Intn(2) == 1 {
+		side += 2
+	}
+	a, b := sides[side], sides[(side+2)%4]
+	return a[r.Intn(len(a))], b[r.Intn(len(b))], true
+}
+
+// distances returns the number of steps from p to every reachable position
+// and the positions in the order they were reached.
+func distances(m *builder.MazeImageMatrix, p Position) ([]Position, map[Position]int) {
+	list := map[Position]int{p: 0}
+	queue := []Position{p}
+	for i := 0; i < len(queue); i++ {
+		for _, n := range Neighbours(m, queue[i]) {
+			if _, ok := list[n]; !ok {
+				list[n] = list[queue[i]] + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return queue, list
+}
+
+// Neighbours returns the path positions next to p inside the maze bounds
+func Neighbours(m *builder.MazeImageMatrix, p Position) []Position {
+	list := make([]Position, 0, 4)
+	bounds := m.Bounds()
+	for _, n := range [4]Position{{p.x - 1, p.y}, {p.x + 1, p.y}, {p.x, p.y - 1}, {p.x, p.y + 1}} {
+		if n.x >= bounds.Min.X && n.x <= bounds.Max.X && n.y >= bounds.Min.Y && n.y <= bounds.Max.Y && m.Has(n.x, n.y, builder.PATH) {
+			list = append(list, n)
+		}
+	}
+	return list
+}
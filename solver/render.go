@@ -0,0 +1,135 @@
+package solver
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"os"
+
+	"github.com/pbergman/maze/builder"
+)
+
+var traceColors = []color.Color{
+	color.RGBA{133, 133, 133, 150}, // visited
+	color.RGBA{255, 0, 0, 150},     // path
+	color.RGBA{150, 200, 255, 150}, // open
+	color.RGBA{180, 130, 200, 150}, // visited from the end
+	color.RGBA{255, 170, 60, 150},  // marked once
+	color.RGBA{90, 90, 90, 150},    // marked twice
+}
+
+// traceColor returns the color a trace is drawn with
+func traceColor(t Trace) color.Color {
+	switch true {
+	case OK == (OK & t.T):
+		return traceColors[1]
+	case OPEN == (OPEN & t.T):
+		return traceColors[2]
+	case BACKWARD == (BACKWARD & t.T):
+		return traceColors[3]
+	case DOUBLE == (DOUBLE & t.T):
+		return traceColors[5]
+	case MARK == (MARK & t.T):
+		return traceColors[4]
+	default:
+		return traceColors[0]
+	}
+}
+
+// drawTraces draws the traces over the image of the matrix
+func drawTraces(m *builder.MazeImageMatrix, traces []Trace) draw.Image {
+	maze := m.DrawImage()
+	ratio := int(m.I.GetRatio())
+	for _, t := range traces {
+		rect := image.Rect(t.X*ratio, t.Y*ratio, t.X*ratio+int(m.I.GetRatio()), t.Y*ratio+int(m.I.GetRatio()))
+		draw.Draw(maze, rect, &image.Uniform{traceColor(t)}, image.ZP, draw.Src)
+	}
+	return maze
+}
+
+// animateTraces writes an animated gif drawing the traces one by one
+func animateTraces(file *os.File, m *builder.MazeImageMatrix, traces []Trace) {
+
+	var palette color.Palette = color.Palette{}
+	palette = append(palette, color.White)
+	palette = append(palette, color.Black)
+	palette = append(palette, traceColors...)
+
+	out := &gif.GIF{}
+	ratio := int(m.I.GetRatio())
+	maze := m.DrawImage()
+
+	if len(traces) <= 1000 {
+		for _, t := range traces {
+			rect := image.Rect(t.X*ratio, t.Y*ratio, t.X*ratio+int(m.I.GetRatio()), t.Y*ratio+int(m.I.GetRatio()))
+			draw.Draw(maze, rect, &image.Uniform{traceColor(t)}, image.ZP, draw.Src)
+
+			pm := image.NewPaletted(maze.Bounds(), palette)
+			draw.FloydSteinberg.Draw(pm, maze.Bounds(), maze, image.ZP)
+			out.Image = append(out.Image, pm)
+			out.Delay = append(out.Delay, 0)
+		}
+	} else {
+		for i := 0; i < len(traces); i++ {
+			rect := image.Rect(traces[i].X*ratio, traces[i].Y*ratio, traces[i].X*ratio+int(m.I.GetRatio()), traces[i].Y*ratio+int(m.I.GetRatio()))
+			draw.Draw(maze, rect, &image.Uniform{traceColor(traces[i])}, image.ZP, draw.Src)
+
+			if i%100 == 0 {
+				pm := image.NewPaletted(maze.Bounds(), palette)
+				draw.FloydSteinberg.Draw(pm, maze.Bounds(), maze, image.ZP)
+				out.Image = append(out.Image, pm)
+				out.Delay = append(out.Delay, 0)
+			}
+		}
+	}
+
+	gif.EncodeAll(file, out)
+}
+
+// renderTraces prints the matrix with the trace found for every position
+func renderTraces(m *builder.MazeImageMatrix, trace func(x, y int) *Trace) string {
+	buff := new(bytes.Buffer)
+	for y, data := range m.M {
+		for x, token := range data {
+
+			if trace := trace(x, y); trace != nil {
+				switch true {
+				case OK == (OK & trace.T):
+					buff.Write([]byte{'*'})
+				case DOUBLE == (DOUBLE & trace.T):
+					buff.Write([]byte{'='})
+				case MARK == (MARK & trace.T):
+					buff.Write([]byte{'-'})
+				case BACKWARD == (BACKWARD & trace.T):
+					buff.Write([]byte{','})
+				case VISITED == (VISITED & trace.T):
+					buff.Write([]byte{'.'})
+				case OPEN == (OPEN & trace.T):
+					buff.Write([]byte{'o'})
+				}
+
+			} else {
+				writeToken(buff, token)
+			}
+
+		}
+		buff.Write([]byte{'\n'})
+	}
+	return string(buff.Bytes())
+}
+
+// writeToken writes the character for a matrix token
+func writeToken(buff *bytes.Buffer, token builder.MatrixToken) {
+	switch true {
+	case builder.WALL == (builder.WALL & token):
+		buff.Write([]byte{'#'})
+	case builder.PATH == (builder.PATH & token), builder.BORDER == (builder.BORDER & token):
+		buff.Write([]byte{' '})
+	case builder.START == (builder.START & token):
+		buff.Write([]byte{'S'})
+	case builder.END == (builder.END & token):
+		buff.Write([]byte{'E'})
+	}
+}
@@ -0,0 +1,271 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/draw"
+	"image/gif"
+	"os"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/pbergman/maze/builder"
+)
+
+// Solver finds a route from start to end in the given maze, solvers should
+// not change the matrix. A solver stops with the error of the context when
+// it is done and with ErrBudgetExceeded when the budget of the context is
+// used up, see WithBudget.
+type Solver interface {
+	Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error)
+}
+
+type Stats struct {
+	Visited     int           `json:"visited"`      // number of explored positions
+	Expanded    int           `json:"expanded"`     // number of positions taken from the open set
+	Backtracks  int           `json:"backtracks"`   // number of times the solver went back
+	Junctions   int           `json:"junctions"`    // number of junctions the solver explored
+	MaxFrontier int           `json:"max_frontier"` // peak size of the open set, queue or stack
+	PathLength  int           `json:"path_length"`  // number of positions on the path
+	Cost        int           `json:"cost"`         // summed cost of the positions on the path, the start excluded
+	Duration    time.Duration `json:"duration"`     // time spend solving
+	Allocated   uint64        `json:"allocated"`    // bytes allocated while solving, by all goroutines
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf(
+		"visited: %d, expanded: %d, backtracks: %d, junctions: %d, max frontier: %d, path length: %d, cost: %d, duration: %s, allocated: %d bytes",
+		s.Visited,
+		s.Expanded,
+		s.Backtracks,
+		s.Junctions,
+		s.MaxFrontier,
+		s.PathLength,
+		s.Cost,
+		s.Duration,
+		s.Allocated,
+	)
+}
+
+// Result holds the route found by a solver and the positions it explored
+type Result struct {
+	Path      []Position          // route from start to end
+	Visited   []Position          // explored (closed) positions in the order they were visited
+	Open      []Position          // positions still queued when the solver stopped
+	Backward  []Position          // the part of the visited positions explored from the end
+	Marks     map[[2]Position]int // Trémaux marks per passage, keyed by Passage
+	Remaining []Position          // positions left by the filling solvers, holding every route
+	Stats     Stats
+	m         *builder.MazeImageMatrix
+}
+
+var solvers = map[string]func() Solver{}
+
+func init() {
+	Register("walker", func() Solver { return DepthFirst{} })
+}
+
+// Register makes a solver available by name, registering a name twice will
+// replace the previous one.
+func Register(name string, factory func() Solver) {
+	solvers[name] = factory
+}
+
+// Get returns a new solver for the given name
+func Get(name string) (Solver, error) {
+	if factory, ok := solvers[name]; ok {
+		return factory(), nil
+	}
+	return nil, fmt.Errorf("solver: unknown solver %q, available: %v", name, Names())
+}
+
+// Names returns the sorted names of all registered solvers
+func Names() []string {
+	names := make([]string, 0, len(solvers))
+	for name := range solvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run validates start and end, solves the maze with the given solver and
+// fills the common stats. The backtracks and junctions are counted from the
+// solver events, an observer already on the context still gets them.
+func Run(ctx context.Context, s Solver, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	if err := Validate(m, start); err != nil {
+		return nil, fmt.Errorf("solver: start %w", err)
+	}
+	if err := Validate(m, end); err != nil {
+		return nil, fmt.Errorf("solver: end %w", err)
+	}
+	var backtracks, junctions int64
+	observer, _ := ctx.Value(observerKey{}).(Observer)
+	ctx = WithObserver(ctx, func(e Event) {
+		switch e.Kind {
+		case BACKTRACK:
+			atomic.AddInt64(&backtracks, 1)
+		case JUNCTION:
+			atomic.AddInt64(&junctions, 1)
+		}
+		if observer != nil {
+			observer(e)
+		}
+	})
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	allocated := memory.TotalAlloc
+	begin := time.Now()
+	result, err := s.Solve(ctx, m, start, end)
+	if result != nil {
+		result.m = m
+		result.Stats.Duration = time.Now().Sub(begin)
+		runtime.ReadMemStats(&memory)
+		result.Stats.Allocated = memory.TotalAlloc - allocated
+		result.Stats.Backtracks = int(atomic.LoadInt64(&backtracks))
+		result.Stats.Junctions = int(atomic.LoadInt64(&junctions))
+		result.Stats.PathLength = len(result.Path)
+		result.Stats.Cost = PathCost(m, result.Path)
+		if result.Stats.Visited == 0 {
+			result.Stats.Visited = len(result.Visited)
+		}
+	}
+	return result, err
+}
+
+// RunGoals runs the solver, as Run, from every start to every end and
+// returns the cheapest route found, routes of the same cost are ordered by
+// length. The stats are those of the run that found the route. A pair that
+// can not be solved is skipped, the error of the last one is returned when
+// no pair is solved. Running out of budget or time stops all pairs.
+func RunGoals(ctx context.Context, s Solver, m *builder.MazeImageMatrix, starts, ends []Position) (*Result, error) {
+	var best, last *Result
+	err := ErrNoSolution
+	for _, start := range starts {
+		for _, end := range ends {
+			result, e := Run(ctx, s, m, start, end)
+			if e != nil {
+				if errors.Is(e, ErrBudgetExceeded) || ctx.Err() != nil {
+					return result, e
+				}
+				last, err = result, e
+				continue
+			}
+			if best == nil || result.Stats.Cost < best.Stats.Cost || (result.Stats.Cost == best.Stats.Cost && result.Stats.PathLength < best.Stats.PathLength) {
+				best = result
+			}
+		}
+	}
+	if best == nil {
+		return last, err
+	}
+	return best, nil
+}
+
+// PathCost returns the cost of walking the path, see MazeImageMatrix.Cost
+func PathCost(m *builder.MazeImageMatrix, path []Position) int {
+	cost := 0
+	for i := 1; i < len(path); i++ {
+		cost += m.Cost(path[i].x, path[i].y)
+	}
+	return cost
+}
+
+// Traces returns the visited positions as traces followed by the open set
+// and the path, so they can be drawn the same as the walker result.
+func (r *Result) Traces() []Trace {
+	traces := make([]Trace, 0, len(r.Visited)+len(r.Open)+len(r.Path))
+	backward := make(map[Position]bool, len(r.Backward))
+	for _, p := range r.Backward {
+		backward[p] = true
+	}
+	for _, p := range r.Visited {
+		if backward[p] {
+			traces = append(traces, Trace{X: p.x, Y: p.y, T: VISITED | BACKWARD})
+		} else {
+			traces = append(traces, Trace{X: p.x, Y: p.y, T: VISITED})
+		}
+	}
+	for _, p := range r.Open {
+		traces = append(traces, Trace{X: p.x, Y: p.y, T: OPEN})
+	}
+	// a position shows the most marks of the passages it is on
+	marks := make(map[Position]int)
+	for e, n := range r.Marks {
+		for _, p := range e {
+			if n > marks[p] {
+				marks[p] = n
+			}
+		}
+	}
+	for _, p := range r.Visited {
+		switch marks[p] {
+		case 1:
+			traces = append(traces, Trace{X: p.x, Y: p.y, T: VISITED | MARK})
+		case 2:
+			traces = append(traces, Trace{X: p.x, Y: p.y, T: VISITED | MARK | DOUBLE})
+		}
+	}
+	for _, p := range r.Remaining {
+		traces = append(traces, Trace{X: p.x, Y: p.y, T: OK})
+	}
+	// the path is drawn last so it shows on top of the explored positions
+	for _, p := range r.Path {
+		traces = append(traces, Trace{X: p.x, Y: p.y, T: VISITED | OK})
+	}
+	return traces
+}
+
+func (r *Result) ToFile(file *os.File) error {
+	return gif.Encode(file, r.DrawImage(), &gif.Options{NumColors: 256})
+}
+
+// DrawImage draws the maze with the explored positions and path
+func (r *Result) DrawImage() draw.Image {
+	return drawTraces(r.m, r.Traces())
+}
+
+// CreateAnimationImage writes an animated gif of the exploration followed by the path
+func (r *Result) CreateAnimationImage(file *os.File) {
+	animateTraces(file, r.m, r.Traces())
+}
+
+// String prints the the matrix to the stdout in visula way
+func (r *Result) String() string {
+	traces := make(map[Position]*Trace)
+	list := r.Traces()
+	for i := range list {
+		traces[Position{list[i].X, list[i].Y}] = &list[i]
+	}
+	return renderTraces(r.m, func(x, y int) *Trace {
+		return traces[Position{x, y}]
+	})
+}
+
+// DepthFirst solves the maze with the Walker
+type DepthFirst struct{}
+
+func (DepthFirst) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	w := &Walker{s: []Position{start}, e: []Position{end}, b: m.Bounds(), m: m}
+	traces, err := w.Solve(ctx)
+	result := &Result{Stats: Stats{Expanded: len(traces.t), MaxFrontier: traces.m}}
+	seen := make(map[Position]bool)
+	for _, t := range traces.t {
+		p := Position{t.X, t.Y}
+		if !seen[p] {
+			seen[p] = true
+			result.Visited = append(result.Visited, p)
+		}
+		if OK == (OK&t.T) && (len(result.Path) == 0 || result.Path[len(result.Path)-1] != p) {
+			result.Path = append(result.Path, p)
+		}
+	}
+	if err != nil {
+		// the route walked so far is not a solution
+		result.Path = nil
+	}
+	return result, err
+}
@@ -0,0 +1,121 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// optimal are the solvers that always return a shortest route
+var optimal = map[string]bool{
+	"astar":         true,
+	"bfs":           true,
+	"bidirectional": true,
+	"cul-de-sac":    true,
+	"dead-end":      true,
+	"dijkstra":      true,
+	"jps":           true,
+}
+
+func TestSolvers(t *testing.T) {
+	tests := []struct {
+		generator string
+		size      int
+	}{
+		{"backtracker", 1},
+		{"backtracker", 15},
+		{"prim", 15},
+		{"braid", 15},
+	}
+	for _, name := range Names() {
+		for _, test := range tests {
+			for seed := int64(1); seed <= 3; seed++ {
+				t.Run(fmt.Sprintf("%s/%s/%d/%d", name, test.generator, test.size, seed), func(t *testing.T) {
+					m, start, end := testMaze(t, test.generator, test.size, seed)
+					s, err := Get(name)
+					if err != nil {
+						t.Fatal(err)
+					}
+					result, err := Run(context.Background(), s, m, start, end)
+					if err != nil {
+						t.Fatal(err)
+					}
+					v := CheckPath(m, start, end, result.Path)
+					if !v.Valid {
+						t.Fatalf("invalid route: %s", v)
+					}
+					if optimal[name] && !v.Optimal {
+						t.Errorf("expected a shortest route: %s", v)
+					}
+					if result.Stats.PathLength != len(result.Path) {
+						t.Errorf("expected path length %d in the stats, got %d", len(result.Path), result.Stats.PathLength)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSolversCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m, start, end := testMaze(t, "backtracker", 15, 1)
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			s, err := Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Run(ctx, s, m, start, end); !errors.Is(err, context.Canceled) {
+				t.Errorf("expected %v, got %v", context.Canceled, err)
+			}
+		})
+	}
+}
+
+func TestRunGoals(t *testing.T) {
+	// two separate corridors, each with an entrance and an exit
+	m, _, _ := drawnMaze(
+		"########",
+		"#S  E  #",
+		"########",
+		"#S    E#",
+		"########",
+	)
+	upper, lower := NewPosition(2, 2), NewPosition(2, 4)
+	tests := []struct {
+		name   string
+		starts []Position
+		ends   []Position
+		route  []Position // first and last position of the expected route
+		err    error
+	}{
+		{"every pair", []Position{lower, upper}, []Position{NewPosition(7, 4), NewPosition(5, 2)}, []Position{upper, NewPosition(5, 2)}, nil},
+		{"lower only", []Position{lower}, []Position{NewPosition(5, 2), NewPosition(7, 4)}, []Position{lower, NewPosition(7, 4)}, nil},
+		{"no pair", []Position{lower}, []Position{NewPosition(5, 2)}, nil, ErrNoSolution},
+	}
+	for _, name := range Names() {
+		for _, test := range tests {
+			t.Run(name+"/"+test.name, func(t *testing.T) {
+				s, err := Get(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err := RunGoals(context.Background(), s, m, test.starts, test.ends)
+				if test.err != nil {
+					if !errors.Is(err, test.err) {
+						t.Fatalf("expected %v, got %v", test.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if first, last := result.Path[0], result.Path[len(result.Path)-1]; first != test.route[0] || last != test.route[1] {
+					t.Errorf("expected a route from %s to %s, got %s to %s", test.route[0], test.route[1], first, last)
+				}
+			})
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package solver
 
+import "fmt"
+
 type WalkToken uint16
 
 const (
 	MULTI WalkToken = 1 << iota
 	OK
 	VISITED
+	OPEN     // queued but not visited
+	BACKWARD // explored from the end
+	MARK     // passage marked once
+	DOUBLE   // passage marked twice, together with MARK
 )
 
 type Position struct {
@@ -13,6 +19,22 @@ type Position struct {
 	y int
 }
 
+func NewPosition(x, y int) Position {
+	return Position{x, y}
+}
+
+func (p Position) X() int {
+	return p.x
+}
+
+func (p Position) Y() int {
+	return p.y
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d,%d", p.x, p.y)
+}
+
 type Trace struct {
 	X  int       // x position of trace
 	Y  int       // y position of trace
@@ -23,76 +45,80 @@ type Trace struct {
 
 type TraceablePosition struct {
 	Position
-	t []Trace // stack of traces
-	s []int   // stack of sections
+	t []Trace          // stack of traces
+	s []int            // stack of sections
+	p []int            // stack of trace indexes on the current route
+	i map[Position]int // index of the first trace for a position
+	m int              // longest the route has been
 }
 
 func NewTraceablePosition(x, y int) *TraceablePosition {
-	return &TraceablePosition{Position{x, y}, make([]Trace, 0), make([]int, 0)}
+	return &TraceablePosition{Position{x, y}, make([]Trace, 0), make([]int, 0), make([]int, 0), make(map[Position]int), 0}
 }
 
-// GoBack will go back to last multi section position and updates the tokens
-func (t *TraceablePosition) GoBack() {
-	var index int
+// GoBack will go back to last multi section position and updates the tokens,
+// it returns false when there is no section with untried directions left.
+func (t *TraceablePosition) GoBack() bool {
+	var index, o int
 
-	// search last multi section position with tries left
-	for i, wt := range t.t {
-		if MULTI == (MULTI&wt.T) && OK == (OK&wt.T) && wt.tr > 0 {
-			index = i
+	// search last multi section position with tries left, the sections on
+	// the stack are the multi positions still on the route
+	for o = len(t.s) - 1; o >= 0; o-- {
+		if t.t[t.s[o]].tr > 0 {
+			index = t.s[o]
+			break
 		}
 	}
 
-	inSlice := func(i int, s []int) bool {
-		for _, l := range s {
-			if l == i {
-				return true
-			}
-		}
+	if o < 0 {
 		return false
 	}
 
-	for o, i := range t.s {
-		if i == index {
-			for i := 0; i < len(t.t); i++ {
-				// remove all ok tokens
-				if inSlice(t.t[i].s, t.s[o:]) {
-					t.t[i].T ^= OK
-				}
-			}
-			t.s = t.s[:o]
-			break
-		}
+	// remove the ok tokens of the route walked since the section
+	for len(t.p) > 0 && t.p[len(t.p)-1] >= index {
+		t.t[t.p[len(t.p)-1]].T &^= OK
+		t.p = t.p[:len(t.p)-1]
 	}
+	t.s = t.s[:o]
 
 	// subtracting counter trying new route
 	t.t[index].tr--
 	// update position with last section position
 	t.y, t.x = t.t[index].Y, t.t[index].X
+	return true
 }
 
 func (t *TraceablePosition) AddTrace(x, y int) {
 
 	if len(t.s) > 0 {
-		t.t = append(t.t, Trace{x, y, OK | VISITED, 0, t.s[len(t.s)-1]})
+		t.add(Trace{x, y, OK | VISITED, 0, t.s[len(t.s)-1]})
 	} else {
-		t.t = append(t.t, Trace{x, y, OK | VISITED, 0, 0})
+		t.add(Trace{x, y, OK | VISITED, 0, 0})
 	}
 
 }
 
 func (t *TraceablePosition) AddTraceSection(x, y int, count int) {
 	t.s = append(t.s, len(t.t))
-	t.t = append(t.t, Trace{x, y, OK | VISITED | MULTI, count, t.s[len(t.s)-1]})
+	t.add(Trace{x, y, OK | VISITED | MULTI, count, t.s[len(t.s)-1]})
+}
+
+// add pushes the trace on the stack and the route and indexes its position
+func (t *TraceablePosition) add(trace Trace) {
+	if _, ok := t.i[Position{trace.X, trace.Y}]; !ok {
+		t.i[Position{trace.X, trace.Y}] = len(t.t)
+	}
+	t.p = append(t.p, len(t.t))
+	t.t = append(t.t, trace)
+	if len(t.p) > t.m {
+		t.m = len(t.p)
+	}
 }
 
 // HasVisited will check if given cordinates are in the trace stack
 func (t *TraceablePosition) HasVisited(x, y int) bool {
-	for _, wt := range t.t {
-		if wt.X == x && wt.Y == y {
-			return true
-		}
-	}
-	return false
+	_, ok := t.i[Position{x, y}]
+	return ok
 }
 
 func (t *TraceablePosition) GetTraces() []Trace {
@@ -100,10 +126,9 @@ func (t *TraceablePosition) GetTraces() []Trace {
 }
 
 func (t *TraceablePosition) GetTrace(x, y int) *Trace {
-	for _, wt := range t.t {
-		if wt.X == x && wt.Y == y {
-			return &wt
-		}
+	if i, ok := t.i[Position{x, y}]; ok {
+		wt := t.t[i]
+		return &wt
 	}
 	return nil
 }
@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pbergman/maze/builder"
+)
+
+// benchmarkSizes are the maze sizes in cells per side, the trace lookups
+// should keep the walker linear in the number of walked positions.
+var benchmarkSizes = []int{50, 200, 500}
+
+// benchmarkWalker returns a walker on a generated maze of n by n cells
+func benchmarkWalker(b *testing.B, n int) *Walker {
+	m, err := builder.NewMazeImageBuilder(n, n).Generate("backtracker", 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w, err := NewWalker(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return w
+}
+
+func BenchmarkWalkerSolve(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", n, n), func(b *testing.B) {
+			w := benchmarkWalker(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := w.Solve(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWalkerString(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", n, n), func(b *testing.B) {
+			w := benchmarkWalker(b, n)
+			if _, err := w.Solve(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = w.String()
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/pbergman/maze/builder"
+)
+
+func init() {
+	Register("tremaux", func() Solver { return Tremaux{} })
+}
+
+// Tremaux solves the maze with Trémaux's algorithm, every passage is marked
+// each time it is walked and a passage with two marks is never entered
+// again. It only needs the marks to find its way, works on mazes with loops
+// and returns ErrNoSolution after it walked back to the start with every
+// passage marked twice. The passages marked once form the route.
+type Tremaux struct{}
+
+func (Tremaux) Solve(ctx context.Context, m *builder.MazeImageMatrix, start, end Position) (*Result, error) {
+	marks := make(map[[2]Position]int)
+	result := &Result{Visited: []Position{start}, Path: []Position{start}, Marks: marks}
+	visited := map[Position]bool{start: true}
+	current, from := start, Position{-1, -1} // from is the previous position
+	known := false                           // current was visited before arriving
+	tracker := newTracker(ctx)
+
+	for current != end {
+		if err := tracker.step(len(marks) + len(result.Visited)); err != nil {
+			return result, err
+		}
+		var next Position
+		exits := Neighbours(m, current)
+
+		switch {
+		// a new passage leading to a known position, go back the same way
+		case known && marks[Passage(from, current)] == 1:
+			next = from
+		default:
+			best := 3
+			for _, n := range exits {
+				if n == from && len(exits) > 1 {
+					continue
+				}
+				if c := marks[Passage(current, n)]; c < best {
+					next, best = n, c
+				}
+			}
+			// every other passage is marked twice, go back the way we came
+			if best >= 2 {
+				if from.x < 0 || marks[Passage(from, current)] >= 2 {
+					return result, ErrNoSolution
+				}
+				next = from
+			}
+		}
+
+		e := Passage(current, next)
+		marks[e]++
+		result.Stats.Expanded++
+
+		// the route holds the passages marked once
+		if marks[e] == 1 {
+			result.Path = append(result.Path, next)
+			if len(result.Path) > result.Stats.MaxFrontier {
+				result.Stats.MaxFrontier = len(result.Path)
+			}
+			tracker.visit(m, next)
+		} else {
+			result.Path = result.Path[:len(result.Path)-1]
+			tracker.emit(BACKTRACK, next)
+		}
+
+		from, current, known = current, next, visited[next]
+		if !known {
+			visited[current] = true
+			result.Visited = append(result.Visited, current)
+		}
+	}
+
+	tracker.emit(FOUND, end)
+	return result, nil
+}
+
+// Passage returns the key of the passage between two adjacent positions as
+// used for Result.Marks, the upper (or left) position comes first.
+func Passage(a, b Position) [2]Position {
+	if b.y < a.y || (b.y == a.y && b.x < a.x) {
+		a, b = b, a
+	}
+	return [2]Position{a, b}
+}
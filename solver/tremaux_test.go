@@ -0,0 +1,121 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pbergman/maze/builder"
+)
+
+// drawnMaze builds a matrix from rows drawn with # for walls and spaces,
+// S and E for paths, the outer walls are part of the drawing. It returns
+// the matrix with the positions of S and E.
+func drawnMaze(rows ...string) (*builder.MazeImageMatrix, Position, Position) {
+	var start, end Position
+	height, width := len(rows)+2, len(rows[0])+2
+	matrix := make([][]builder.MatrixToken, height)
+	for y := range matrix {
+		matrix[y] = make([]builder.MatrixToken, width)
+		for x := range matrix[y] {
+			switch {
+			case y == 0 || x == 0:
+				matrix[y][x] = builder.BORDER
+			case y == height-1 || x == width-1:
+				matrix[y][x] = builder.PATH
+			case rows[y-1][x-1] == '#':
+				matrix[y][x] = builder.WALL
+			default:
+				matrix[y][x] = builder.PATH
+				if rows[y-1][x-1] == 'S' {
+					start = NewPosition(x, y)
+				}
+				if rows[y-1][x-1] == 'E' {
+					end = NewPosition(x, y)
+				}
+			}
+		}
+	}
+	return &builder.MazeImageMatrix{M: matrix}, start, end
+}
+
+func TestTremauxMarks(t *testing.T) {
+	// the dead end on the left is tried first and walked back
+	m, start, end := drawnMaze(
+		"#######",
+		"#  S E#",
+		"#######",
+	)
+	result, err := Tremaux{}.Solve(context.Background(), m, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		a, b  Position
+		marks int
+	}{
+		{NewPosition(2, 2), NewPosition(3, 2), 2},
+		{NewPosition(4, 2), NewPosition(3, 2), 2},
+		{NewPosition(4, 2), NewPosition(5, 2), 1},
+		{NewPosition(6, 2), NewPosition(5, 2), 1},
+	}
+	for _, test := range tests {
+		if n := result.Marks[Passage(test.a, test.b)]; n != test.marks {
+			t.Errorf("expected %d marks on %s - %s, got %d", test.marks, test.a, test.b, n)
+		}
+	}
+	if len(result.Marks) != len(tests) {
+		t.Errorf("expected %d marked passages, got %d", len(tests), len(result.Marks))
+	}
+	if v := CheckPath(m, start, end, result.Path); !v.Valid || !v.Optimal {
+		t.Errorf("expected the shortest path, got %s", v)
+	}
+}
+
+func TestTremaux(t *testing.T) {
+	for _, generator := range []string{"backtracker", "prim", "braid"} {
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", generator, seed), func(t *testing.T) {
+				m, start, end := testMaze(t, generator, 10, seed)
+				result, err := Tremaux{}.Solve(context.Background(), m, start, end)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v := CheckPath(m, start, end, result.Path); !v.Valid {
+					t.Fatalf("invalid path: %s", v)
+				}
+				for e, n := range result.Marks {
+					if n < 1 || n > 2 {
+						t.Errorf("passage %s - %s has %d marks", e[0], e[1], n)
+					}
+				}
+				// the route is made of the passages marked once
+				for i := 1; i < len(result.Path); i++ {
+					if n := result.Marks[Passage(result.Path[i-1], result.Path[i])]; n != 1 {
+						t.Errorf("route passage %s - %s has %d marks", result.Path[i-1], result.Path[i], n)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTremauxNoSolution(t *testing.T) {
+	m, start, end := drawnMaze(
+		"#######",
+		"#  S#E#",
+		"### ###",
+		"#     #",
+		"#######",
+	)
+	result, err := Tremaux{}.Solve(context.Background(), m, start, end)
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected %v, got %v", ErrNoSolution, err)
+	}
+	for e, n := range result.Marks {
+		if n != 2 {
+			t.Errorf("expected every passage marked twice, %s - %s has %d marks", e[0], e[1], n)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package solver
 
 import (
-	"bytes"
+	"context"
+	"fmt"
 	"github.com/pbergman/maze/builder"
 	"image"
-	"image/color"
 	"image/draw"
 	"image/gif"
 	"os"
@@ -35,57 +35,129 @@ const (
 )
 
 type Walker struct {
-	s Position                 // start point
-	e Position                 // end point
+	s []Position               // start points, the first is used to walk from
+	e []Position               // end points, reaching any of them solves the maze
 	b image.Rectangle          // maze bounds, to set borders
 	m *builder.MazeImageMatrix //
 	r *TraceablePosition       // result
 }
 
-// NewWalker initialize walker and determine the start/end points
-func NewWalker(m *builder.MazeImageMatrix) *Walker {
+// Option configures the walker on creation
+type Option func(w *Walker) error
 
-	w := &Walker{
-		b: image.Rect(1, 1, len(m.M[0])-2, len(m.M)-2),
-		m: m,
+// StartAt sets an explicit start position, the matrix is not changed so
+// the position only applies to this walker, see Mark
+func StartAt(x, y int) Option {
+	return func(w *Walker) error {
+		p := Position{x, y}
+		if err := Validate(w.m, p); err != nil {
+			return fmt.Errorf("solver: start %w", err)
+		}
+		w.s = []Position{p}
+		return nil
 	}
+}
 
-	done := false
-	check := func(p Position, walker *Walker) bool {
-		if walker.m.Has(p.x, p.y, builder.PATH) {
-			if walker.s.x == 0 && walker.s.y == 0 {
-				walker.s.y, walker.s.x = p.y, p.x
-				walker.m.M[p.y][p.x] |= builder.START
-				return false
-			}
-			if walker.e.x == 0 && walker.e.y == 0 {
-				walker.e.y, walker.e.x = p.y, p.x
-				walker.m.M[p.y][p.x] |= builder.END
-				return true
-			}
+// EndAt sets an explicit end position, the matrix is not changed so the
+// position only applies to this walker, see Mark
+func EndAt(x, y int) Option {
+	return func(w *Walker) error {
+		p := Position{x, y}
+		if err := Validate(w.m, p); err != nil {
+			return fmt.Errorf("solver: end %w", err)
 		}
-		return false
+		w.e = []Position{p}
+		return nil
 	}
+}
 
-	pos := Position{1, 1}
+// Entrance will only walk from the start with given index, see Goals
+func Entrance(i int) Option {
+	return func(w *Walker) error {
+		if i < 0 || i >= len(w.s) {
+			return fmt.Errorf("solver: no entrance %s, maze has %d", Label(i), len(w.s))
+		}
+		w.s = []Position{w.s[i]}
+		return nil
+	}
+}
 
-	for w.right(&pos) {
-		done = check(pos, w)
+// Exit will only accept the end with given index as solution, see Goals
+func Exit(i int) Option {
+	return func(w *Walker) error {
+		if i < 0 || i >= len(w.e) {
+			return fmt.Errorf("solver: no exit %s, maze has %d", Label(i), len(w.e))
+		}
+		w.e = []Position{w.e[i]}
+		return nil
+	}
+}
+
+// Closest will only walk from the entrance to the exit closest to it,
+// apply it after Entrance and Exit to pick from the selected ones
+func Closest() Option {
+	return func(w *Walker) error {
+		path, err := route(w.m, w.s, func(p Position) bool { return w.isEnd(p.x, p.y) })
+		if err != nil {
+			return err
+		}
+		w.s, w.e = []Position{path[0]}, []Position{path[len(path)-1]}
+		return nil
 	}
+}
+
+// NewWalker initialize walker and determine the start/end points. The start
+// and end tokens on the matrix are used when available else they are placed
+// by scanning the border, the options are applied after that.
+func NewWalker(m *builder.MazeImageMatrix, options ...Option) (*Walker, error) {
 
-	for !done && w.down(&pos) {
-		done = check(pos, w)
+	w := &Walker{
+		b: m.Bounds(),
+		m: m,
 	}
 
-	for !done && w.left(&pos) {
-		done = check(pos, w)
+	w.s, w.e = Goals(m)
+
+	if len(w.s) == 0 || len(w.e) == 0 {
+		start, end, ok := scan(m)
+		if !ok {
+			return nil, ErrNoPlacement
+		}
+		if err := PlaceAt(m, start, end); err != nil {
+			return nil, err
+		}
+		w.s, w.e = []Position{start}, []Position{end}
 	}
 
-	for !done && w.up(&pos) {
-		done = check(pos, w)
+	for _, option := range options {
+		if err := option(w); err != nil {
+			return nil, err
+		}
 	}
 
-	return w
+	return w, nil
+}
+
+// Mark replaces the start and end tokens on the matrix with the start and
+// end points of the walker
+func (w *Walker) Mark() {
+	unmark(w.m, builder.START|builder.END)
+	for _, p := range w.s {
+		w.m.M[p.y][p.x] |= builder.START
+	}
+	for _, p := range w.e {
+		w.m.M[p.y][p.x] |= builder.END
+	}
+}
+
+// isEnd checks if given position is one of the accepted end points
+func (w *Walker) isEnd(x, y int) bool {
+	for _, e := range w.e {
+		if e.x == x && e.y == y {
+			return true
+		}
+	}
+	return false
 }
 
 // peekAround will return array with available direction based on current position
@@ -119,89 +191,65 @@ func (w *Walker) ToFile(file *os.File) error {
 
 // DrawImage draws a new image beased on matrix and config ration
 func (w Walker) DrawImage() draw.Image {
-	maze := w.m.DrawImage()
-	ratio := int(w.m.I.GetRatio())
-	for _, t := range w.r.t {
-		rect := image.Rect(t.X*ratio, t.Y*ratio, t.X*ratio+int(w.m.I.GetRatio()), t.Y*ratio+int(w.m.I.GetRatio()))
-		switch true {
-		case OK == (OK & t.T):
-			draw.Draw(maze, rect, &image.Uniform{color.RGBA{255, 0, 0, 150}}, image.ZP, draw.Src)
-		default:
-			draw.Draw(maze, rect, &image.Uniform{color.RGBA{133, 133, 133, 150}}, image.ZP, draw.Src)
-		}
-	}
-	return maze
+	return drawTraces(w.m, w.r.t)
 }
 
 // DrawImage draws a new image beased on matrix and config ration
 func (w Walker) CreateAnimationImage(file *os.File) {
+	animateTraces(file, w.m, w.r.t)
+}
 
-	var palette color.Palette = color.Palette{}
-	palette = append(palette, color.White)
-	palette = append(palette, color.Black)
-	palette = append(palette, color.RGBA{133, 133, 133, 150})
-	palette = append(palette, color.RGBA{255, 0, 0, 150})
-
-	out := &gif.GIF{}
-	ratio := int(w.m.I.GetRatio())
-	maze := w.m.DrawImage()
-
-	if len(w.r.t) <= 1000 {
-		for _, t := range w.r.t {
-			rect := image.Rect(t.X*ratio, t.Y*ratio, t.X*ratio+int(w.m.I.GetRatio()), t.Y*ratio+int(w.m.I.GetRatio()))
-			switch true {
-			case OK == (OK & t.T):
-				draw.Draw(maze, rect, &image.Uniform{color.RGBA{255, 0, 0, 150}}, image.ZP, draw.Src)
-			default:
-				draw.Draw(maze, rect, &image.Uniform{color.RGBA{133, 133, 133, 150}}, image.ZP, draw.Src)
-			}
-
-			pm := image.NewPaletted(maze.Bounds(), palette)
-			draw.FloydSteinberg.Draw(pm, maze.Bounds(), maze, image.ZP)
-			out.Image = append(out.Image, pm)
-			out.Delay = append(out.Delay, 0)
-		}
-	} else {
-		for i := 0; i < len(w.r.t); i++ {
-			rect := image.Rect(w.r.t[i].X*ratio, w.r.t[i].Y*ratio, w.r.t[i].X*ratio+int(w.m.I.GetRatio()), w.r.t[i].Y*ratio+int(w.m.I.GetRatio()))
-			switch true {
-			case OK == (OK & w.r.t[i].T):
-				draw.Draw(maze, rect, &image.Uniform{color.RGBA{255, 0, 0, 150}}, image.ZP, draw.Src)
-			default:
-				draw.Draw(maze, rect, &image.Uniform{color.RGBA{133, 133, 133, 150}}, image.ZP, draw.Src)
-			}
-
-			if i%100 == 0 {
-				pm := image.NewPaletted(maze.Bounds(), palette)
-				draw.FloydSteinberg.Draw(pm, maze.Bounds(), maze, image.ZP)
-				out.Image = append(out.Image, pm)
-				out.Delay = append(out.Delay, 0)
-			}
+// Will try to solve give maze, the entrances are tried in order till one
+// of them reaches an exit. The walked traces are returned also when solving
+// fails with ErrNoSolution or because the context is done.
+func (w *Walker) Solve(ctx context.Context) (*TraceablePosition, error) {
+	tracker := newTracker(ctx)
+	for i, start := range w.s {
+		walker, err := w.walk(tracker, start)
+		if err != ErrNoSolution || i == len(w.s)-1 {
+			return walker, err
 		}
 	}
-
-	gif.EncodeAll(file, out)
+	return nil, ErrNoSolution
 }
 
-// Will try to solve give maze
-func (w *Walker) Solve() {
+// walk goes depth first from the start till it reaches an exit
+func (w *Walker) walk(tracker *tracker, start Position) (*TraceablePosition, error) {
 
-	walker := NewTraceablePosition(w.s.x, w.s.y)
-	walker.AddTrace(w.s.x, w.s.y)
+	walker := NewTraceablePosition(start.x, start.y)
+	walker.AddTrace(start.x, start.y)
+	w.r = walker
+	back := false // walker went back to a junction
 
 	for {
 
-		if builder.END == (builder.END & w.m.M[walker.y][walker.x]) {
+		if err := tracker.step(len(walker.t)); err != nil {
+			return walker, err
+		}
+
+		if !back {
+			tracker.emit(VISIT, walker.Position)
+		}
+		back = false
+
+		if w.isEnd(walker.x, walker.y) {
 			walker.AddTrace(walker.x, walker.y)
+			tracker.emit(FOUND, walker.Position)
 			break
 		}
 
 		if peek := w.peekAround(*walker); len(peek) == 0 {
 			walker.AddTrace(walker.x, walker.y)
-			walker.GoBack()
+			tracker.emit(DEADEND, walker.Position)
+			if !walker.GoBack() {
+				return walker, ErrNoSolution
+			}
+			tracker.emit(BACKTRACK, walker.Position)
+			back = true
 		} else {
 
 			if len(peek) > 1 {
+				tracker.emit(JUNCTION, walker.Position)
 				walker.AddTraceSection(walker.x, walker.y, len(peek)-1)
 			} else {
 				walker.AddTrace(walker.x, walker.y)
@@ -220,7 +268,7 @@ func (w *Walker) Solve() {
 		}
 	}
 
-	w.r = walker
+	return walker, nil
 }
 
 func (m *Walker) left(p *Position) bool {
@@ -257,35 +305,27 @@ func (m *Walker) down(p *Position) bool {
 
 // String prints the the matrix to the stdout in visula way
 func (w Walker) String() string {
-	buff := new(bytes.Buffer)
-	for y, data := range w.m.M {
-		for x, token := range data {
-
-			if trace := w.r.GetTrace(x, y); trace != nil {
-				switch true {
-				case OK == (OK & trace.T):
-					buff.Write([]byte{'*'})
-				case VISITED == (VISITED & trace.T):
-					buff.Write([]byte{'.'})
-				}
+	return renderTraces(w.m, w.r.GetTrace)
+}
 
-			} else {
-				switch true {
-				case builder.WALL == (builder.WALL & token):
-					buff.Write([]byte{'#'})
-				case builder.PATH == (builder.PATH & token), builder.BORDER == (builder.BORDER & token):
-					buff.Write([]byte{' '})
-				case builder.START == (builder.START & token):
-					buff.Write([]byte{'S'})
-				case builder.END == (builder.END & token):
-					buff.Write([]byte{'E'})
-				}
-			}
+// GetStart returns the position the walker starts from
+func (w *Walker) GetStart() Position {
+	return w.s[0]
+}
 
-		}
-		buff.Write([]byte{'\n'})
-	}
-	return string(buff.Bytes())
+// GetEnd returns the first accepted end position
+func (w *Walker) GetEnd() Position {
+	return w.e[0]
+}
+
+// GetStarts returns the positions the walker may start from
+func (w *Walker) GetStarts() []Position {
+	return w.s
+}
+
+// GetEnds returns all accepted end positions
+func (w *Walker) GetEnds() []Position {
+	return w.e
 }
 
 func (w *Walker) GetResult() *TraceablePosition {